@@ -15,8 +15,12 @@ import (
     "github.com/cloud-wave-best-zizon/product-service/internal/repository"
     "github.com/cloud-wave-best-zizon/product-service/internal/service"
     "github.com/cloud-wave-best-zizon/product-service/pkg/config"
+    "github.com/cloud-wave-best-zizon/product-service/pkg/health"
+    "github.com/cloud-wave-best-zizon/product-service/pkg/logging"
     "github.com/cloud-wave-best-zizon/product-service/pkg/middleware"
+    "github.com/cloud-wave-best-zizon/product-service/pkg/metrics"
     pkgtls "github.com/cloud-wave-best-zizon/product-service/pkg/tls"
+    "github.com/cloud-wave-best-zizon/product-service/pkg/tracing"
 	"crypto/tls"
     "github.com/gin-gonic/gin"
     "github.com/joho/godotenv"
@@ -29,14 +33,23 @@ func main() {
         log.Println("No .env file found, using environment variables")
     }
 
-    logger, _ := zap.NewProduction()
-    defer logger.Sync()
-
     cfg, err := config.Load()
     if err != nil {
         log.Fatal("Failed to load config:", err)
     }
 
+    // LOG_LEVEL은 관리용 포트의 /log/level로 재시작 없이 변경 가능
+    logger, logLevel, err := logging.New(logging.Config{
+        Level:              cfg.LogLevel,
+        Format:             cfg.LogFormat,
+        SamplingInitial:    cfg.LogSamplingInitial,
+        SamplingThereafter: cfg.LogSamplingThereafter,
+    })
+    if err != nil {
+        log.Fatal("Failed to create logger:", err)
+    }
+    defer logger.Sync()
+
     tlsConfig := &pkgtls.TLSConfig{}
     if err := envconfig.Process("", tlsConfig); err != nil {
         logger.Fatal("Failed to load TLS config", zap.Error(err))
@@ -44,45 +57,245 @@ func main() {
 
     logger.Info("Service configuration",
         zap.String("port", cfg.Port),
+        zap.String("log_level", logLevel.String()),
+        zap.String("log_format", cfg.LogFormat),
         zap.Bool("kafka_enabled", cfg.KafkaEnabled),
+        zap.Strings("kafka_brokers", cfg.KafkaBrokerList()),
+        zap.String("kafka_order_topic", cfg.KafkaOrderTopic),
+        zap.String("kafka_stock_topic", cfg.KafkaStockTopic),
+        zap.String("kafka_reply_topic", cfg.KafkaReplyTopic),
         zap.Bool("tls_enabled", tlsConfig.Enabled),
+        zap.Bool("kafka_tls_enabled", cfg.KafkaTLSEnabled),
+        zap.String("kafka_sasl_mechanism", cfg.KafkaSASLMechanism),
         zap.Bool("internal_tls", os.Getenv("INTERNAL_TLS_ENABLED") == "true"))
 
+    // 분산 추적 (로컬 모드는 none 또는 stdout 권장)
+    shutdownTracing, err := tracing.Init(context.Background(), tracing.Config{
+        ServiceName:  "product-service",
+        Exporter:     cfg.TracingExporter,
+        OTLPEndpoint: cfg.TracingOTLPEndpoint,
+        OTLPInsecure: cfg.TracingOTLPInsecure,
+        SampleRatio:  cfg.TracingSampleRatio,
+    }, logger)
+    if err != nil {
+        logger.Fatal("Failed to initialize tracing", zap.Error(err))
+    }
+
+    // /livez, /readyz 에서 확인할 의존성 (사용하는 컴포넌트만 등록)
+    healthChecker := health.NewChecker(cfg.HealthCheckTimeout)
+
     // Initialize components
     dynamoClient, err := repository.NewDynamoDBClient(cfg)
     if err != nil {
         log.Fatal("Failed to create DynamoDB client:", err)
     }
 
-    productRepo := repository.NewProductRepository(dynamoClient, cfg.ProductTableName)
-    productService := service.NewProductService(productRepo, logger)
+    var store repository.Store
+    if dynamoClient == nil {
+        store = repository.NewMemoryProductStore()
+        logger.Info("Using in-memory product store")
+    } else {
+        dynamoStore := repository.NewDynamoProductStore(dynamoClient, repository.TableNames{
+            Products:     cfg.ProductTableName,
+            Reservations: cfg.ReservationTableName,
+            Outbox:       cfg.OutboxTableName,
+            Processed:    cfg.ProcessedEventTableName,
+        }, logger)
+        store = dynamoStore
+        healthChecker.AddReadiness(health.Check{Name: "dynamodb", Check: dynamoStore.Ping})
+        logger.Info("Using DynamoDB product store",
+            zap.String("table", cfg.ProductTableName),
+            zap.String("reservation_table", cfg.ReservationTableName),
+            zap.String("outbox_table", cfg.OutboxTableName),
+            zap.String("processed_event_table", cfg.ProcessedEventTableName))
+    }
+
+    // 재고 이벤트는 아웃박스에 기록된 뒤 릴레이가 Kafka로 발행 (Kafka 비활성화 시 기록하지 않음)
+    stockTopic, replyTopic := "", ""
+    if cfg.KafkaEnabled {
+        stockTopic = cfg.KafkaStockTopic
+        replyTopic = cfg.KafkaReplyTopic
+    }
+    stockKeyStrategy, err := events.ParseKeyStrategy(cfg.KafkaStockKeyStrategy)
+    if err != nil {
+        logger.Fatal("Invalid Kafka producer config", zap.Error(err))
+    }
+    eventCodec, err := events.NewCodec(cfg.KafkaEventEncoding)
+    if err != nil {
+        logger.Fatal("Invalid Kafka producer config", zap.Error(err))
+    }
+
+    productService := service.NewProductService(store, store, service.ProductServiceConfig{
+        StockTopic:       stockTopic,
+        ReplyTopic:       replyTopic,
+        StockKeyStrategy: stockKeyStrategy,
+        DedupeTTL:        cfg.DedupeTTL,
+        Codec:            eventCodec,
+    }, logger)
     productHandler := handler.NewProductHandler(productService, logger)
+    reservationService := service.NewReservationService(store, cfg.ReservationTTL, cfg.ReservationMaxTTL, logger)
+    reservationHandler := handler.NewReservationHandler(reservationService, logger)
+
+    // 백그라운드 작업 (만료 예약 해제, 아웃박스 릴레이 등)
+    bgCtx, bgCancel := context.WithCancel(context.Background())
+    defer bgCancel()
+
+    go reservationService.RunSweeper(bgCtx, cfg.ReservationSweepInterval)
+
+    // Outbox Relay (Kafka Producer)
+    var outboxRelay *events.OutboxRelay
+    var kafkaProducer *events.KafkaProducer
+    var kafkaSecurity events.KafkaSecurity
+    if cfg.KafkaEnabled {
+        if cfg.KafkaTLSEnabled {
+            kafkaSecurity.TLS, err = pkgtls.LoadKafkaTLSConfig(pkgtls.KafkaTLSOptions{
+                CAFile:             cfg.KafkaTLSCAFile,
+                CertFile:           cfg.KafkaTLSCertFile,
+                KeyFile:            cfg.KafkaTLSKeyFile,
+                ServerName:         cfg.KafkaTLSServerName,
+                InsecureSkipVerify: cfg.KafkaTLSInsecureSkipVerify,
+                UseSVID:            cfg.KafkaTLSUseSVID,
+                SocketPath:         tlsConfig.SocketPath,
+            }, logger)
+            if err != nil {
+                logger.Fatal("Failed to load Kafka TLS config", zap.Error(err))
+            }
+        }
+        kafkaSecurity.SASL, err = events.NewSASLMechanism(cfg.KafkaSASLMechanism, cfg.KafkaSASLUsername, cfg.KafkaSASLPassword)
+        if err != nil {
+            logger.Fatal("Failed to configure Kafka SASL", zap.Error(err))
+        }
+        kafkaSecurity.LogConnection(logger, cfg.KafkaBrokerList())
+
+        orderKeyStrategy, err := events.ParseKeyStrategy(cfg.KafkaOrderKeyStrategy)
+        if err != nil {
+            logger.Fatal("Invalid Kafka producer config", zap.Error(err))
+        }
+        balancer, err := events.NewBalancer(cfg.KafkaProducerBalancer)
+        if err != nil {
+            logger.Fatal("Invalid Kafka producer config", zap.Error(err))
+        }
+
+        kafkaProducer, err = events.NewKafkaProducer(events.KafkaProducerConfig{
+            Brokers:          cfg.KafkaBrokerList(),
+            OrderTopic:       cfg.KafkaOrderTopic,
+            OrderKeyStrategy: orderKeyStrategy,
+            Balancer:         balancer,
+            Codec:            eventCodec,
+            Security:         kafkaSecurity,
+        }, logger)
+        if err != nil {
+            logger.Fatal("Failed to create Kafka producer", zap.Error(err))
+        }
+        defer kafkaProducer.Close()
 
-    // Kafka Consumer
+        brokers := cfg.KafkaBrokerList()
+        healthChecker.AddReadiness(health.Check{Name: "kafka", Check: func(ctx context.Context) error {
+            return events.PingBrokers(ctx, brokers, kafkaSecurity)
+        }})
+
+        outboxRelay = events.NewOutboxRelay(store, kafkaProducer, events.OutboxRelayConfig{
+            PollInterval: cfg.OutboxPollInterval,
+            BatchSize:    cfg.OutboxBatchSize,
+            RetryBase:    cfg.OutboxRetryBase,
+            RetryMax:     cfg.OutboxRetryMax,
+        }, logger)
+        go outboxRelay.Run(bgCtx)
+    }
+
+    // Kafka Consumer (처리 실패 메시지는 DLQ로 전송)
     var kafkaConsumer *events.KafkaConsumer
+    var dlqHandler *handler.DLQHandler
     if cfg.KafkaEnabled {
-        kafkaConsumer = events.NewKafkaConsumer(cfg.KafkaBrokers, productService, logger)
+        startOffset, err := events.ParseStartOffset(cfg.KafkaStartOffset)
+        if err != nil {
+            logger.Fatal("Invalid Kafka consumer config", zap.Error(err))
+        }
+        if cfg.KafkaOrdering != events.OrderingPartition && cfg.KafkaOrdering != events.OrderingKey {
+            logger.Fatal("Invalid Kafka consumer config", zap.String("ordering", cfg.KafkaOrdering))
+        }
+
+        kafkaConsumer = events.NewKafkaConsumer(events.KafkaConsumerConfig{
+            Brokers:        cfg.KafkaBrokerList(),
+            Topic:          cfg.KafkaOrderTopic,
+            GroupID:        cfg.KafkaGroupID,
+            StartOffset:    startOffset,
+            MinBytes:       cfg.KafkaMinBytes,
+            MaxBytes:       cfg.KafkaMaxBytes,
+            CommitInterval: cfg.KafkaCommitInterval,
+            Workers:        cfg.KafkaWorkers,
+            Ordering:       cfg.KafkaOrdering,
+            Security:       kafkaSecurity,
+            DLQTopic:       cfg.KafkaDLQTopic,
+            Retry: events.RetryPolicy{
+                MaxAttempts: cfg.ConsumerRetryMaxAttempts,
+                BaseBackoff: cfg.ConsumerRetryBase,
+                MaxBackoff:  cfg.ConsumerRetryMax,
+            },
+        }, productService, kafkaProducer, logger)
         defer kafkaConsumer.Close()
         
         ctx, cancel := context.WithCancel(context.Background())
         defer cancel()
         
-        go kafkaConsumer.StartConsuming(ctx)
+        kafkaConsumer.Start(ctx)
         logger.Info("Kafka consumer started")
+
+        healthChecker.AddLiveness(health.Check{Name: "kafka_consumer_loop", Check: func(context.Context) error {
+            return kafkaConsumer.Alive()
+        }})
+        healthChecker.AddReadiness(health.Check{Name: "kafka_consumer", Check: func(context.Context) error {
+            return kafkaConsumer.HealthCheck()
+        }})
+
+        dlqReplayer := events.NewDLQReplayer(events.DLQReplayerConfig{
+            Brokers:      cfg.KafkaBrokerList(),
+            DLQTopic:     cfg.KafkaDLQTopic,
+            GroupID:      cfg.KafkaDLQReplayGroupID,
+            DefaultTopic: cfg.KafkaOrderTopic,
+            IdleTimeout:  5 * time.Second,
+            Security:     kafkaSecurity,
+        }, kafkaProducer, logger)
+        dlqHandler = handler.NewDLQHandler(dlqReplayer, logger)
     }
 
+    // SPIRE SVID를 mTLS 서버나 Kafka 클라이언트 인증서로 쓰면 유효한 SVID가 있어야 트래픽을 받음
+    internalTLS := os.Getenv("INTERNAL_TLS_ENABLED") == "true" && tlsConfig.Enabled
+    if internalTLS || (cfg.KafkaEnabled && cfg.KafkaTLSEnabled && cfg.KafkaTLSUseSVID) {
+        healthChecker.AddReadiness(health.Check{Name: "svid", Check: func(context.Context) error {
+            return pkgtls.ValidateSVID()
+        }})
+    }
+    logger.Info("Health checks registered", zap.Strings("components", healthChecker.Names()))
+    healthHandler := handler.NewHealthHandler(healthChecker, logger)
+
     // Setup Gin Router
     router := gin.New()
     router.Use(gin.Recovery())
+    router.Use(middleware.Tracing())
+    router.Use(middleware.Metrics())
     router.Use(middleware.Logger(logger))
     router.Use(middleware.RequestID())
 
+    // Kubernetes probes
+    router.GET("/livez", healthHandler.Livez)
+    router.GET("/readyz", healthHandler.Readyz)
+
     // Routes
     v1 := router.Group("/api/v1")
     {
         v1.POST("/products", productHandler.CreateProduct)
+        v1.GET("/products", productHandler.ListProducts)
         v1.GET("/products/:id", productHandler.GetProduct)
+        v1.PUT("/products/:id", productHandler.UpdateProduct)
+        v1.PATCH("/products/:id", productHandler.PatchProduct)
+        v1.DELETE("/products/:id", productHandler.DeleteProduct)
         v1.POST("/products/:id/deduct", productHandler.DeductStock)
+        v1.POST("/stock/deduct-batch", productHandler.DeductStockBatch)
+        v1.POST("/reservations", reservationHandler.CreateReservation)
+        v1.GET("/reservations/:id", reservationHandler.GetReservation)
+        v1.POST("/reservations/:id/confirm", reservationHandler.ConfirmReservation)
+        v1.POST("/reservations/:id/release", reservationHandler.ReleaseReservation)
         v1.GET("/health", func(c *gin.Context) {
             status := gin.H{
                 "status": "healthy",
@@ -90,10 +303,34 @@ func main() {
                 "kafka": cfg.KafkaEnabled,
                 "internal_tls": os.Getenv("INTERNAL_TLS_ENABLED") == "true",
             }
+            if outboxRelay != nil {
+                status["outbox"] = outboxRelay.Lag()
+            }
+            if kafkaConsumer != nil {
+                status["consumer"] = kafkaConsumer.Stats()
+            }
             c.JSON(200, status)
         })
     }
 
+    // Prometheus /metrics, 로그 레벨 변경, DLQ 재처리는 ALB에 노출되지 않는 관리용 포트에서만 제공
+    // (ADMIN_PORT가 비어 있으면 공개 포트에 노출하지 않고 비활성화)
+    var adminRouter *gin.Engine
+    if cfg.AdminPort != "" {
+        adminRouter = gin.New()
+        adminRouter.Use(gin.Recovery())
+        adminRouter.Use(middleware.RequestID())
+        adminRouter.GET("/metrics", gin.WrapH(metrics.Handler()))
+        logLevelHandler := handler.NewLogLevelHandler(logLevel, logger)
+        adminRouter.GET("/log/level", logLevelHandler.GetLevel)
+        adminRouter.PUT("/log/level", logLevelHandler.SetLevel)
+        if dlqHandler != nil {
+            adminRouter.POST("/dlq/replay", dlqHandler.ReplayDLQ)
+        }
+    } else {
+        logger.Warn("ADMIN_PORT is empty, admin endpoints (/metrics, /log/level, /dlq/replay) are disabled")
+    }
+
     var wg sync.WaitGroup
     servers := []*http.Server{}
 
@@ -113,6 +350,24 @@ func main() {
         }
     }()
 
+    // Admin Server (metrics)
+    if cfg.AdminPort != "" {
+        adminServer := &http.Server{
+            Addr:    ":" + cfg.AdminPort,
+            Handler: adminRouter,
+        }
+        servers = append(servers, adminServer)
+
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            logger.Info("Starting admin server", zap.String("port", cfg.AdminPort))
+            if err := adminServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+                logger.Fatal("Admin server failed", zap.Error(err))
+            }
+        }()
+    }
+
     // mTLS Server for service-to-service (port 8443)
     if os.Getenv("INTERNAL_TLS_ENABLED") == "true" {
         tlsCfg, err := pkgtls.LoadTLSConfig(tlsConfig, logger)
@@ -154,6 +409,7 @@ func main() {
     if kafkaConsumer != nil {
         kafkaConsumer.Stop()
     }
+    bgCancel()
 
     ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
     defer cancel()
@@ -165,5 +421,9 @@ func main() {
     }
     
     wg.Wait()
+
+    if err := shutdownTracing(ctx); err != nil {
+        logger.Error("Failed to flush traces", zap.Error(err))
+    }
     logger.Info("All servers stopped")
 }
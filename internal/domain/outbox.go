@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"time"
+)
+
+type OutboxStatus string
+
+const (
+	OutboxPending OutboxStatus = "pending"
+	OutboxSent    OutboxStatus = "sent"
+)
+
+// OutboxMessage - 재고 변경과 같은 트랜잭션으로 기록되어 나중에 Kafka로 발행되는 메시지
+type OutboxMessage struct {
+	MessageID     string            `dynamodbav:"message_id"                json:"message_id"`
+	Topic         string            `dynamodbav:"topic"                     json:"topic"`
+	Key           string            `dynamodbav:"key"                       json:"key"`
+	Payload       []byte            `dynamodbav:"payload"                   json:"payload"`
+	Headers       map[string]string `dynamodbav:"headers,omitempty"         json:"headers,omitempty"` // 이벤트 타입, 스키마 버전, 요청 ID, trace context
+	Status        OutboxStatus      `dynamodbav:"status"                    json:"status"`
+	Attempts      int               `dynamodbav:"attempts"                  json:"attempts"`
+	LastError     string            `dynamodbav:"last_error,omitempty"      json:"last_error,omitempty"`
+	NextAttemptAt time.Time         `dynamodbav:"next_attempt_at,unixtime"  json:"next_attempt_at"`
+	CreatedAt     time.Time         `dynamodbav:"created_at"                json:"created_at"`
+}
+
+// OutboxLag - 아직 발행되지 않은 아웃박스 메시지 현황
+type OutboxLag struct {
+	Pending          int     `json:"pending"`
+	OldestAgeSeconds float64 `json:"oldest_age_seconds"`
+	LastError        string  `json:"last_error,omitempty"`
+}
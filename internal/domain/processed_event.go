@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrDuplicateEvent - 이미 처리된 이벤트/주문 (컨슈머가 건너뛸 수 있도록 서비스/저장소가 공통으로 사용)
+var ErrDuplicateEvent = errors.New("event already processed")
+
+// 처리 완료 기록 종류 (주문 ID 기록은 종류별로 하나씩 남는다)
+const (
+	ProcessedActionDeduct  = "deduct"  // 주문 생성: 재고 차감 또는 거절
+	ProcessedActionRestock = "restock" // 주문 취소: 차감했던 재고 복구
+)
+
+// ProcessedEvent - 처리 완료된 이벤트 기록 (at-least-once 재전달 시 중복 처리 방지)
+// 저장소는 EventID와 OrderID(Action별) 각각에 대해 기록을 남기며, ExpiresAt 이후에는 없는 것으로 취급한다.
+type ProcessedEvent struct {
+	EventID string
+	OrderID int
+	Action  string // 비어 있으면 ProcessedActionDeduct
+	// Items - 실제로 차감한 상품과 수량 (주문 취소 시 이 수량만 복구, 거절된 주문은 비어 있음)
+	Items       []StockDeductionItem
+	ProcessedAt time.Time
+	ExpiresAt   time.Time
+}
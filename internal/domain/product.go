@@ -1,6 +1,10 @@
 package domain
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"time"
 )
 
@@ -9,6 +13,9 @@ type Product struct {
     Name      string    `dynamodbav:"name"       json:"name"`
     Price     float64   `dynamodbav:"price"      json:"price"`
     Stock     int       `dynamodbav:"stock"      json:"stock"`
+    // 예약(Reservation)으로 잡혀 있어 판매할 수 없는 수량 (Stock과 별도)
+    Reserved  int       `dynamodbav:"reserved"   json:"reserved"`
+    Version   int64     `dynamodbav:"version"    json:"version"`
     CreatedAt time.Time `dynamodbav:"created_at" json:"created_at"`
     UpdatedAt time.Time `dynamodbav:"updated_at" json:"updated_at"`
 }
@@ -20,8 +27,36 @@ type CreateProductRequest struct {
     Stock     int     `json:"stock"      binding:"required"`
 }
 
+// UpdateProductRequest - PUT 요청 (전체 교체)
+type UpdateProductRequest struct {
+    Name  string   `json:"name"  binding:"required"`
+    Price *float64 `json:"price" binding:"required,gte=0"`
+    Stock *int     `json:"stock" binding:"required,gte=0"`
+}
+
 type DeductStockRequest struct {
 	Quantity int `json:"quantity" binding:"required,min=1"`
+	// OrderID - 차감을 일으킨 주문 (선택)
+	OrderID int `json:"order_id"`
+}
+
+// StockDeductionItem - 일괄 차감 요청의 상품별 항목
+type StockDeductionItem struct {
+	ProductID string `json:"product_id" binding:"required"`
+	Quantity  int    `json:"quantity"   binding:"required,min=1"`
+}
+
+type BatchDeductStockRequest struct {
+	Items   []StockDeductionItem `json:"items"    binding:"required,min=1,dive"`
+	OrderID int                  `json:"order_id"`
+}
+
+// StockOrigin - 재고 변경을 일으킨 주문/요청 정보 (발행되는 이벤트에 실림)
+type StockOrigin struct {
+	OrderID   int
+	RequestID string
+	// EventID - 원본 이벤트 ID (사가 이벤트 처리 시 이 ID와 OrderID로 같은 이벤트/주문의 중복 차감을 막는다)
+	EventID string
 }
 
 type ProductResponse struct {
@@ -29,6 +64,13 @@ type ProductResponse struct {
     Name      string  `json:"name"`
     Price     float64 `json:"price"`
     Stock     int     `json:"stock"`
+    Reserved  int     `json:"reserved"`
+    Version   int64   `json:"version"`
+}
+
+type ProductListResponse struct {
+	Items      []ProductResponse `json:"items"`
+	NextCursor string            `json:"next_cursor"`
 }
 
 type StockDeductionResponse struct {
@@ -37,3 +79,92 @@ type StockDeductionResponse struct {
 	NewStock      int    `json:"new_stock"`
 	Deducted      int    `json:"deducted"`
 }
+
+type BatchStockDeductionResponse struct {
+	Items []StockDeductionResponse `json:"items"`
+}
+
+// StockRestockResponse - 주문 취소로 복구된 상품 재고
+type StockRestockResponse struct {
+	ProductID     string `json:"product_id"`
+	PreviousStock int    `json:"previous_stock"`
+	NewStock      int    `json:"new_stock"`
+	Restocked     int    `json:"restocked"`
+}
+
+var ErrInvalidPatch = errors.New("invalid patch")
+
+// ErrInvalidBatch - 빈 요청, 잘못된 수량 등 처리할 수 없는 재고 차감 요청 (재시도해도 성공하지 않음)
+var ErrInvalidBatch = errors.New("invalid stock deduction batch")
+
+// StockItemError - 일괄 차감 중 실패한 상품 정보 (Err는 원인 sentinel 에러)
+type StockItemError struct {
+	ProductID string
+	Available int
+	Err       error
+}
+
+func (e *StockItemError) Error() string {
+	return fmt.Sprintf("product %s: %v", e.ProductID, e.Err)
+}
+
+func (e *StockItemError) Unwrap() error {
+	return e.Err
+}
+
+// 머지 패치로 변경할 수 없는 필드
+var immutableProductFields = map[string]bool{
+	"product_id": true,
+	"version":    true,
+	"reserved":   true,
+	"created_at": true,
+	"updated_at": true,
+}
+
+var mutableProductFields = map[string]bool{
+	"name":  true,
+	"price": true,
+	"stock": true,
+}
+
+// ApplyMergePatch - JSON Merge Patch (RFC 7396)를 상품에 적용
+// 모든 필드가 필수이므로 null(필드 삭제)은 허용하지 않는다.
+func (p *Product) ApplyMergePatch(patch []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(patch, &fields); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+	if len(fields) == 0 {
+		return fmt.Errorf("%w: empty patch", ErrInvalidPatch)
+	}
+
+	for name, value := range fields {
+		if immutableProductFields[name] {
+			return fmt.Errorf("%w: field %q is immutable", ErrInvalidPatch, name)
+		}
+		if !mutableProductFields[name] {
+			return fmt.Errorf("%w: unknown field %q", ErrInvalidPatch, name)
+		}
+		if bytes.Equal(bytes.TrimSpace(value), []byte("null")) {
+			return fmt.Errorf("%w: field %q cannot be removed", ErrInvalidPatch, name)
+		}
+	}
+
+	patched := *p
+	if err := json.Unmarshal(patch, &patched); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+
+	if patched.Name == "" {
+		return fmt.Errorf("%w: name must not be empty", ErrInvalidPatch)
+	}
+	if patched.Price < 0 {
+		return fmt.Errorf("%w: price must not be negative", ErrInvalidPatch)
+	}
+	if patched.Stock < 0 {
+		return fmt.Errorf("%w: stock must not be negative", ErrInvalidPatch)
+	}
+
+	*p = patched
+	return nil
+}
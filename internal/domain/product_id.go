@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// MaxProductIDLength - DynamoDB 키와 Kafka 메시지 키로 그대로 쓰이는 상품 ID의 최대 길이
+const MaxProductIDLength = 64
+
+// ErrInvalidProductID - 형식에 맞지 않는 상품 ID
+var ErrInvalidProductID = errors.New("invalid product id")
+
+// 영문/숫자로 시작하고 영문, 숫자, '-', '_', '.', ':'만 사용
+var productIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._:-]*$`)
+
+// NormalizeProductID - 앞뒤 공백을 제거하고 상품 ID 형식을 검증
+// 숫자 ID(주문 이벤트의 product_id: 42)도 문자열 "42"와 같은 상품으로 취급한다.
+func NormalizeProductID(id string) (string, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return "", fmt.Errorf("%w: empty", ErrInvalidProductID)
+	}
+	if len(id) > MaxProductIDLength {
+		return "", fmt.Errorf("%w: longer than %d characters", ErrInvalidProductID, MaxProductIDLength)
+	}
+	if !productIDPattern.MatchString(id) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidProductID, id)
+	}
+	return id, nil
+}
+
+// ProductID - 문자열("P-100")과 정수(42) JSON 값을 모두 받는 상품 ID (이벤트 역직렬화용)
+// 정수는 10진수 문자열로 바꾸며, 형식 검증은 NormalizeProductID에서 한다.
+type ProductID string
+
+func (id *ProductID) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		*id = ProductID(s)
+		return nil
+	}
+
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("%w: product_id must be a string or an integer", ErrInvalidProductID)
+	}
+	if strings.ContainsAny(n.String(), ".eE") || strings.HasPrefix(n.String(), "-") {
+		return fmt.Errorf("%w: product_id %s is not a non-negative integer", ErrInvalidProductID, n)
+	}
+	*id = ProductID(n.String())
+	return nil
+}
+
+func (id ProductID) String() string {
+	return string(id)
+}
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"time"
+)
+
+type ReservationStatus string
+
+const (
+	ReservationPending   ReservationStatus = "pending"
+	ReservationConfirmed ReservationStatus = "confirmed"
+	ReservationReleased  ReservationStatus = "released"
+	ReservationExpired   ReservationStatus = "expired"
+)
+
+// Reservation - 결제 완료 전까지 재고를 잡아두는 예약
+// 예약된 수량은 Product.Stock에서 빠져 Product.Reserved로 옮겨지며,
+// 확정(confirm) 시 소진되고 해제(release)/만료 시 Stock으로 되돌아간다.
+type Reservation struct {
+	ReservationID string            `dynamodbav:"reservation_id"       json:"reservation_id"`
+	ProductID     string            `dynamodbav:"product_id"           json:"product_id"`
+	Quantity      int               `dynamodbav:"quantity"             json:"quantity"`
+	Status        ReservationStatus `dynamodbav:"status"               json:"status"`
+	ExpiresAt     time.Time         `dynamodbav:"expires_at,unixtime"  json:"expires_at"`
+	CreatedAt     time.Time         `dynamodbav:"created_at"           json:"created_at"`
+	UpdatedAt     time.Time         `dynamodbav:"updated_at"           json:"updated_at"`
+}
+
+type CreateReservationRequest struct {
+	ProductID string `json:"product_id" binding:"required"`
+	Quantity  int    `json:"quantity"   binding:"required,min=1"`
+	// TTLSeconds - 예약 유지 시간 (생략 시 서버 기본값)
+	TTLSeconds int `json:"ttl_seconds" binding:"omitempty,min=1"`
+}
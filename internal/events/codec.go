@@ -0,0 +1,202 @@
+package events
+
+import (
+    "encoding/json"
+    "errors"
+    "fmt"
+
+    "github.com/cloud-wave-best-zizon/product-service/internal/domain"
+    "github.com/cloud-wave-best-zizon/product-service/internal/events/eventspb"
+    "github.com/segmentio/kafka-go"
+    "google.golang.org/protobuf/proto"
+    "google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// 페이로드 인코딩 (content-type 헤더, 없으면 JSON)
+const (
+    HeaderContentType   = "content-type"
+    ContentTypeJSON     = "application/json"
+    ContentTypeProtobuf = "application/x-protobuf"
+)
+
+// 설정값 (KAFKA_EVENT_ENCODING)
+const (
+    EncodingJSON     = "json"
+    EncodingProtobuf = "protobuf"
+)
+
+// ErrUnsupportedEvent - 코덱에 스키마가 없는 이벤트 타입
+var ErrUnsupportedEvent = errors.New("event type not supported by codec")
+
+// Codec - 이벤트 페이로드 인코딩 (메시지의 content-type 헤더로 선택)
+type Codec interface {
+    ContentType() string
+    Marshal(event interface{}) ([]byte, error)
+    Unmarshal(data []byte, event interface{}) error
+}
+
+var (
+    JSONCodec     Codec = jsonCodec{}
+    ProtobufCodec Codec = protobufCodec{}
+)
+
+// NewCodec - 설정값(json | protobuf)에 맞는 코덱
+func NewCodec(encoding string) (Codec, error) {
+    switch encoding {
+    case EncodingJSON:
+        return JSONCodec, nil
+    case EncodingProtobuf:
+        return ProtobufCodec, nil
+    default:
+        return nil, fmt.Errorf("invalid event encoding %q (expected json or protobuf)", encoding)
+    }
+}
+
+// codecFromHeaders - content-type 헤더로 코덱 선택 (헤더가 없는 기존 메시지는 JSON)
+func codecFromHeaders(headers []kafka.Header) (Codec, error) {
+    switch contentType := headerValue(headers, HeaderContentType); contentType {
+    case "", ContentTypeJSON:
+        return JSONCodec, nil
+    case ContentTypeProtobuf:
+        return ProtobufCodec, nil
+    default:
+        return nil, fmt.Errorf("%w: unsupported content-type %q", errMalformedEvent, contentType)
+    }
+}
+
+// EncodeEvent - 선호 코덱으로 인코딩하고, 스키마가 없는 이벤트는 JSON으로 대체 (실제 사용한 코덱 반환)
+func EncodeEvent(preferred Codec, event interface{}) ([]byte, Codec, error) {
+    if preferred == nil {
+        preferred = JSONCodec
+    }
+    data, err := preferred.Marshal(event)
+    if errors.Is(err, ErrUnsupportedEvent) && preferred != JSONCodec {
+        data, err = JSONCodec.Marshal(event)
+        return data, JSONCodec, err
+    }
+    return data, preferred, err
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) ContentType() string { return ContentTypeJSON }
+
+func (jsonCodec) Marshal(event interface{}) ([]byte, error) {
+    return json.Marshal(event)
+}
+
+func (jsonCodec) Unmarshal(data []byte, event interface{}) error {
+    return json.Unmarshal(data, event)
+}
+
+// protobufCodec - proto/events/v1/events.proto 스키마 (OrderCreated, StockDeducted)
+type protobufCodec struct{}
+
+func (protobufCodec) ContentType() string { return ContentTypeProtobuf }
+
+func (protobufCodec) Marshal(event interface{}) ([]byte, error) {
+    switch e := event.(type) {
+    case OrderCreatedEvent:
+        return orderCreatedToProto(&e)
+    case *OrderCreatedEvent:
+        return orderCreatedToProto(e)
+    case StockDeductedEvent:
+        return proto.Marshal(stockDeductedToProto(&e))
+    case *StockDeductedEvent:
+        return proto.Marshal(stockDeductedToProto(e))
+    default:
+        return nil, fmt.Errorf("%w: %T", ErrUnsupportedEvent, event)
+    }
+}
+
+func (protobufCodec) Unmarshal(data []byte, event interface{}) error {
+    switch e := event.(type) {
+    case *OrderCreatedEvent:
+        var msg eventspb.OrderCreated
+        if err := proto.Unmarshal(data, &msg); err != nil {
+            return err
+        }
+        return orderCreatedFromProto(&msg, e)
+    case *StockDeductedEvent:
+        var msg eventspb.StockDeducted
+        if err := proto.Unmarshal(data, &msg); err != nil {
+            return err
+        }
+        stockDeductedFromProto(&msg, e)
+        return nil
+    default:
+        return fmt.Errorf("%w: %T", ErrUnsupportedEvent, event)
+    }
+}
+
+func orderCreatedToProto(e *OrderCreatedEvent) ([]byte, error) {
+    msg := &eventspb.OrderCreated{
+        EventId:     e.EventID,
+        OrderId:     int64(e.OrderID),
+        UserId:      e.UserID,
+        TotalAmount: e.TotalAmount,
+        Items:       make([]*eventspb.OrderItem, 0, len(e.Items)),
+        Status:      e.Status,
+        Timestamp:   timestamppb.New(e.Timestamp),
+        RequestId:   e.RequestID,
+    }
+    for _, item := range e.Items {
+        msg.Items = append(msg.Items, &eventspb.OrderItem{
+            ProductId:   item.ProductID.String(),
+            ProductName: item.ProductName,
+            Quantity:    int32(item.Quantity),
+            Price:       item.Price,
+        })
+    }
+    return proto.Marshal(msg)
+}
+
+func orderCreatedFromProto(msg *eventspb.OrderCreated, e *OrderCreatedEvent) error {
+    *e = OrderCreatedEvent{
+        EventID:     msg.GetEventId(),
+        OrderID:     int(msg.GetOrderId()),
+        UserID:      msg.GetUserId(),
+        TotalAmount: msg.GetTotalAmount(),
+        Items:       make([]OrderItem, 0, len(msg.GetItems())),
+        Status:      msg.GetStatus(),
+        RequestID:   msg.GetRequestId(),
+    }
+    if msg.Timestamp != nil {
+        e.Timestamp = msg.GetTimestamp().AsTime()
+    }
+    for _, item := range msg.GetItems() {
+        e.Items = append(e.Items, OrderItem{
+            ProductID:   domain.ProductID(item.GetProductId()),
+            ProductName: item.GetProductName(),
+            Quantity:    int(item.GetQuantity()),
+            Price:       item.GetPrice(),
+        })
+    }
+    return nil
+}
+
+func stockDeductedToProto(e *StockDeductedEvent) *eventspb.StockDeducted {
+    return &eventspb.StockDeducted{
+        EventId:   e.EventID,
+        OrderId:   int64(e.OrderID),
+        ProductId: e.ProductID,
+        Quantity:  int32(e.Quantity),
+        NewStock:  int32(e.NewStock),
+        Timestamp: timestamppb.New(e.Timestamp),
+        RequestId: e.RequestID,
+    }
+}
+
+func stockDeductedFromProto(msg *eventspb.StockDeducted, e *StockDeductedEvent) {
+    *e = StockDeductedEvent{
+        EventID:   msg.GetEventId(),
+        OrderID:   int(msg.GetOrderId()),
+        ProductID: msg.GetProductId(),
+        Quantity:  int(msg.GetQuantity()),
+        NewStock:  int(msg.GetNewStock()),
+        RequestID: msg.GetRequestId(),
+    }
+    if msg.Timestamp != nil {
+        e.Timestamp = msg.GetTimestamp().AsTime()
+    }
+}
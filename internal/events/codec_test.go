@@ -0,0 +1,225 @@
+package events
+
+import (
+    "context"
+    "errors"
+    "reflect"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/cloud-wave-best-zizon/product-service/internal/events/eventspb"
+    "github.com/segmentio/kafka-go"
+    "google.golang.org/protobuf/proto"
+    "google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// encodedMessage - 프로듀서와 같은 방식으로 인코딩하고 표준 헤더를 붙인 메시지
+func encodedMessage(t *testing.T, codec Codec, eventType string, event interface{}) kafka.Message {
+    t.Helper()
+
+    value, used, err := EncodeEvent(codec, event)
+    if err != nil {
+        t.Fatalf("encode %T: %v", event, err)
+    }
+    if used != codec {
+        t.Fatalf("encode %T: used %s, want %s", event, used.ContentType(), codec.ContentType())
+    }
+    return kafka.Message{
+        Value:   value,
+        Headers: kafkaHeaders(MessageHeaders(context.Background(), eventType, 1, used.ContentType(), "req-1")),
+    }
+}
+
+func TestCodecRoundTrip(t *testing.T) {
+    timestamp := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+    orderCreated := &OrderCreatedEvent{
+        EventID:     "evt-1",
+        OrderID:     42,
+        UserID:      "user-1",
+        TotalAmount: 30000,
+        Items: []OrderItem{
+            {ProductID: "PROD001", ProductName: "Keyboard", Quantity: 2, Price: 10000},
+            {ProductID: "PROD002", ProductName: "Mouse", Quantity: 1, Price: 10000},
+        },
+        Status:    "PENDING",
+        Timestamp: timestamp,
+        RequestID: "req-1",
+    }
+    stockDeducted := &StockDeductedEvent{
+        EventID:   "evt-2",
+        OrderID:   42,
+        ProductID: "PROD001",
+        Quantity:  2,
+        NewStock:  8,
+        Timestamp: timestamp,
+        RequestID: "req-1",
+    }
+
+    tests := []struct {
+        name      string
+        eventType string
+        event     interface{}
+        decoded   func() interface{}
+    }{
+        {"OrderCreated", EventTypeOrderCreated, orderCreated, func() interface{} { return &OrderCreatedEvent{} }},
+        {"StockDeducted", EventTypeStockDeducted, stockDeducted, func() interface{} { return &StockDeductedEvent{} }},
+    }
+
+    for _, codec := range []Codec{JSONCodec, ProtobufCodec} {
+        for _, tt := range tests {
+            t.Run(codec.ContentType()+"/"+tt.name, func(t *testing.T) {
+                msg := encodedMessage(t, codec, tt.eventType, tt.event)
+
+                selected, err := codecFromHeaders(msg.Headers)
+                if err != nil {
+                    t.Fatalf("codecFromHeaders: %v", err)
+                }
+                if selected != codec {
+                    t.Fatalf("codecFromHeaders selected %s, want %s", selected.ContentType(), codec.ContentType())
+                }
+
+                got := tt.decoded()
+                if err := selected.Unmarshal(msg.Value, got); err != nil {
+                    t.Fatalf("unmarshal: %v", err)
+                }
+                if !reflect.DeepEqual(got, tt.event) {
+                    t.Errorf("round trip mismatch\n got: %+v\nwant: %+v", got, tt.event)
+                }
+            })
+        }
+    }
+}
+
+// 컨슈머 경로: decodeMessage가 헤더로 코덱을 골라 envelope를 만들고 DecodePayload로 복원
+func TestDecodeMessageOrderCreated(t *testing.T) {
+    want := &OrderCreatedEvent{
+        EventID:   "evt-1",
+        OrderID:   7,
+        Items:     []OrderItem{{ProductID: "PROD001", Quantity: 3}},
+        Timestamp: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+    }
+
+    for _, codec := range []Codec{JSONCodec, ProtobufCodec} {
+        t.Run(codec.ContentType(), func(t *testing.T) {
+            envelope, err := decodeMessage(encodedMessage(t, codec, EventTypeOrderCreated, want))
+            if err != nil {
+                t.Fatalf("decodeMessage: %v", err)
+            }
+            if envelope.Type != EventTypeOrderCreated || envelope.Version != 1 {
+                t.Fatalf("envelope = %s v%d, want %s v1", envelope.Type, envelope.Version, EventTypeOrderCreated)
+            }
+
+            var got OrderCreatedEvent
+            if err := envelope.DecodePayload(&got); err != nil {
+                t.Fatalf("DecodePayload: %v", err)
+            }
+            if !reflect.DeepEqual(&got, want) {
+                t.Errorf("round trip mismatch\n got: %+v\nwant: %+v", &got, want)
+            }
+        })
+    }
+}
+
+func TestDecodeMessageRejectsUnknownContentType(t *testing.T) {
+    msg := kafka.Message{
+        Value: []byte(`<order/>`),
+        Headers: []kafka.Header{
+            {Key: HeaderContentType, Value: []byte("application/xml")},
+            {Key: HeaderEventType, Value: []byte(EventTypeOrderCreated)},
+            {Key: HeaderSchemaVersion, Value: []byte("1")},
+        },
+    }
+
+    if _, err := codecFromHeaders(msg.Headers); !errors.Is(err, errMalformedEvent) {
+        t.Errorf("codecFromHeaders error = %v, want errMalformedEvent", err)
+    }
+    if _, err := decodeMessage(msg); !errors.Is(err, errMalformedEvent) {
+        t.Errorf("decodeMessage error = %v, want errMalformedEvent", err)
+    }
+}
+
+// schemaField - 발행된 스키마에서 바꿀 수 없는 필드 번호/이름/타입
+type schemaField struct {
+    number protoreflect.FieldNumber
+    name   string
+    kind   protoreflect.Kind
+}
+
+// TestProtobufSchemaCompatibility - 이미 발행된 스키마의 필드 번호/이름/타입을 고정하고, 같은 이벤트의 JSON 필드와 이름이 일치하는지 확인
+// 필드를 추가할 때는 새 번호로 이 목록에 덧붙이고, 기존 항목은 바꾸거나 지우지 않는다 (삭제한 필드는 proto에 reserved로 남긴다).
+func TestProtobufSchemaCompatibility(t *testing.T) {
+    tests := []struct {
+        message    proto.Message
+        jsonStruct interface{}
+        fields     []schemaField
+    }{
+        {&eventspb.OrderCreated{}, OrderCreatedEvent{}, []schemaField{
+            {1, "event_id", protoreflect.StringKind},
+            {2, "order_id", protoreflect.Int64Kind},
+            {3, "user_id", protoreflect.StringKind},
+            {4, "total_amount", protoreflect.DoubleKind},
+            {5, "items", protoreflect.MessageKind},
+            {6, "status", protoreflect.StringKind},
+            {7, "timestamp", protoreflect.MessageKind},
+            {8, "request_id", protoreflect.StringKind},
+        }},
+        {&eventspb.OrderItem{}, OrderItem{}, []schemaField{
+            {1, "product_id", protoreflect.StringKind},
+            {2, "product_name", protoreflect.StringKind},
+            {3, "quantity", protoreflect.Int32Kind},
+            {4, "price", protoreflect.DoubleKind},
+        }},
+        {&eventspb.StockDeducted{}, StockDeductedEvent{}, []schemaField{
+            {1, "event_id", protoreflect.StringKind},
+            {2, "order_id", protoreflect.Int64Kind},
+            {3, "product_id", protoreflect.StringKind},
+            {4, "quantity", protoreflect.Int32Kind},
+            {5, "new_stock", protoreflect.Int32Kind},
+            {6, "timestamp", protoreflect.MessageKind},
+            {7, "request_id", protoreflect.StringKind},
+        }},
+    }
+
+    for _, tt := range tests {
+        descriptor := tt.message.ProtoReflect().Descriptor()
+        t.Run(string(descriptor.Name()), func(t *testing.T) {
+            fields := descriptor.Fields()
+            for _, want := range tt.fields {
+                field := fields.ByNumber(want.number)
+                if field == nil {
+                    t.Errorf("field %d (%s) was removed, reserve it instead", want.number, want.name)
+                    continue
+                }
+                if string(field.Name()) != want.name || field.Kind() != want.kind {
+                    t.Errorf("field %d = %s %s, want %s %s", want.number, field.Name(), field.Kind(), want.name, want.kind)
+                }
+            }
+
+            jsonFields := jsonFieldNames(tt.jsonStruct)
+            for i := 0; i < fields.Len(); i++ {
+                name := string(fields.Get(i).Name())
+                if !jsonFields[name] {
+                    t.Errorf("protobuf field %q has no matching JSON field in %T", name, tt.jsonStruct)
+                }
+                delete(jsonFields, name)
+            }
+            for name := range jsonFields {
+                t.Errorf("JSON field %q of %T is missing from the protobuf schema", name, tt.jsonStruct)
+            }
+        })
+    }
+}
+
+// jsonFieldNames - 구조체의 JSON 필드 이름 집합
+func jsonFieldNames(v interface{}) map[string]bool {
+    names := make(map[string]bool)
+    typ := reflect.TypeOf(v)
+    for i := 0; i < typ.NumField(); i++ {
+        name, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+        if name != "" && name != "-" {
+            names[name] = true
+        }
+    }
+    return names
+}
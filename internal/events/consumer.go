@@ -2,120 +2,404 @@ package events
 
 import (
     "context"
-    "encoding/json"
+    "errors"
     "fmt"
     "strconv"
+    "strings"
+    "sync"
+    "sync/atomic"
+    "time"
 
+    "github.com/cloud-wave-best-zizon/product-service/internal/domain"
+    "github.com/cloud-wave-best-zizon/product-service/pkg/logging"
+    "github.com/cloud-wave-best-zizon/product-service/pkg/metrics"
+    "github.com/cloud-wave-best-zizon/product-service/pkg/tracing"
     "github.com/segmentio/kafka-go"
-    "github.com/cloud-wave-best-zizon/product-service/internal/service"
+    "go.opentelemetry.io/otel/attribute"
+    "go.opentelemetry.io/otel/codes"
+    "go.opentelemetry.io/otel/trace"
     "go.uber.org/zap"
 )
 
+// StockService - 컨슈머가 의존하는 재고 서비스 (테스트 시 fake 주입 가능)
+// 차감 결과(성공/실패)는 서비스가 사가 응답 이벤트로 기록한다.
+type StockService interface {
+    DeductStockForOrder(ctx context.Context, items []domain.StockDeductionItem, origin domain.StockOrigin) ([]domain.StockDeductionResponse, error)
+    RestockForOrder(ctx context.Context, origin domain.StockOrigin) ([]domain.StockRestockResponse, error)
+}
+
+type KafkaConsumerConfig struct {
+    Brokers        []string
+    Topic          string
+    GroupID        string
+    StartOffset    int64 // kafka.FirstOffset 또는 kafka.LastOffset (커밋된 오프셋이 없을 때)
+    MinBytes       int
+    MaxBytes       int
+    CommitInterval time.Duration // 0이면 동기 커밋
+    DLQTopic       string
+    Retry          RetryPolicy
+    Security       KafkaSecurity
+    Workers        int    // 동시에 처리하는 워커 수 (1이면 순차 처리)
+    Ordering       string // OrderingPartition 또는 OrderingKey
+}
+
+// ParseStartOffset - "first"/"last"를 kafka 시작 오프셋으로 변환
+func ParseStartOffset(value string) (int64, error) {
+    switch strings.ToLower(value) {
+    case "", "first", "earliest":
+        return kafka.FirstOffset, nil
+    case "last", "latest":
+        return kafka.LastOffset, nil
+    }
+    return 0, fmt.Errorf("invalid start offset %q (want first or last)", value)
+}
+
+// messageReader - 컨슈머가 사용하는 kafka.Reader 기능 (벤치마크에서 가짜 구현으로 교체)
+type messageReader interface {
+    FetchMessage(ctx context.Context) (kafka.Message, error)
+    CommitMessages(ctx context.Context, msgs ...kafka.Message) error
+    Close() error
+}
+
 type KafkaConsumer struct {
-    reader         *kafka.Reader
-    productService *service.ProductService
+    reader         messageReader
+    productService StockService
+    deadLetters    MessagePublisher
+    cfg            KafkaConsumerConfig
     logger         *zap.Logger
     cancel         context.CancelFunc
     ctx            context.Context
+
+    registry *HandlerRegistry
+    wg       sync.WaitGroup // 실행 중인 읽기 루프 (Stop 시 대기)
+
+    running     atomic.Bool
+    lastFetchAt atomic.Int64 // 마지막으로 메시지를 읽은 시각 (UnixNano)
+    lastErrAt   atomic.Int64 // 마지막 읽기/그룹 참여 오류 시각 (UnixNano)
+    lastErr     atomic.Value // 마지막 오류 메시지 (string)
+
+    duplicatesSkipped atomic.Int64
+    ordersRejected    atomic.Int64
+    deadLettered      atomic.Int64
+    unknownSkipped    atomic.Int64
 }
 
-func NewKafkaConsumer(brokers string, productService *service.ProductService, logger *zap.Logger) *KafkaConsumer {
-    reader := kafka.NewReader(kafka.ReaderConfig{
-        Brokers:     []string{brokers},
-        Topic:       "order-events",
-        GroupID:     "product-service-consumer",
-        MinBytes:    10e3,
-        MaxBytes:    10e6,
-        StartOffset: kafka.FirstOffset,
-    })
-    
+// ConsumerStats - 컨슈머 처리 통계 (health 엔드포인트 노출용)
+type ConsumerStats struct {
+    DuplicatesSkipped int64 `json:"duplicates_skipped"`
+    OrdersRejected    int64 `json:"orders_rejected"`
+    DeadLettered      int64 `json:"dead_lettered"`
+    UnknownSkipped    int64 `json:"unknown_types_skipped"`
+}
+
+// NewKafkaConsumer - 처리할 수 없는 메시지는 deadLetters로 cfg.DLQTopic에 전송
+func NewKafkaConsumer(cfg KafkaConsumerConfig, productService StockService, deadLetters MessagePublisher, logger *zap.Logger) *KafkaConsumer {
     ctx, cancel := context.WithCancel(context.Background())
-    
-    return &KafkaConsumer{
-        reader:         reader,
+
+    c := &KafkaConsumer{
         productService: productService,
+        deadLetters:    deadLetters,
+        cfg:            cfg,
         logger:         logger,
         ctx:            ctx,
         cancel:         cancel,
+        registry:       NewHandlerRegistry(),
     }
+    // 브로커 연결, 그룹 참여 오류는 Reader 내부에서 재시도되어 FetchMessage로 전달되지 않으므로 ErrorLogger로 수집
+    c.reader = kafka.NewReader(kafka.ReaderConfig{
+        Brokers:        cfg.Brokers,
+        Topic:          cfg.Topic,
+        GroupID:        cfg.GroupID,
+        MinBytes:       cfg.MinBytes,
+        MaxBytes:       cfg.MaxBytes,
+        StartOffset:    cfg.StartOffset,
+        CommitInterval: cfg.CommitInterval,
+        Dialer:         cfg.Security.dialer(),
+        ErrorLogger:    kafka.LoggerFunc(c.readerError),
+    })
+    c.Handle(EventTypeOrderCreated, c.handleOrderCreated)
+    c.Handle(EventTypeOrderCancelled, c.handleOrderCancelled)
+    return c
 }
 
-// HealthCheck 메서드 추가
-func (c *KafkaConsumer) HealthCheck() error {
+// Handle - 이벤트 타입별 처리 함수 등록 (Start 전에 호출)
+func (c *KafkaConsumer) Handle(eventType string, handler HandlerFunc) {
+    c.registry.Register(eventType, handler)
+}
+
+// readerErrorWindow - 이 시간 안에 발생한 오류 이후 메시지를 읽지 못했으면 비정상으로 판단
+const readerErrorWindow = 30 * time.Second
+
+// Alive - 읽기 루프가 실행 중인지 확인 (liveness 용)
+func (c *KafkaConsumer) Alive() error {
     if c.reader == nil {
         return fmt.Errorf("kafka reader not initialized")
     }
+    if !c.running.Load() {
+        return errors.New("consumer loop is not running")
+    }
     return nil
 }
 
-// Stop 메서드 추가
+// HealthCheck - 읽기 루프가 실행 중이고 최근 오류 이후 메시지를 다시 읽었는지 확인 (readiness 용)
+func (c *KafkaConsumer) HealthCheck() error {
+    if err := c.Alive(); err != nil {
+        return err
+    }
+    errAt := c.lastErrAt.Load()
+    if errAt > c.lastFetchAt.Load() && time.Since(time.Unix(0, errAt)) < readerErrorWindow {
+        reason, _ := c.lastErr.Load().(string)
+        return fmt.Errorf("kafka reader error: %s", reason)
+    }
+    return nil
+}
+
+// readerError - Reader 내부 오류 기록 (HealthCheck에서 사용)
+func (c *KafkaConsumer) readerError(msg string, args ...interface{}) {
+    reason := fmt.Sprintf(msg, args...)
+    c.lastErr.Store(reason)
+    c.lastErrAt.Store(time.Now().UnixNano())
+    c.logger.Debug("Kafka reader error", zap.String("error", reason))
+}
+
+// Stats - 재전달로 건너뛴 중복 이벤트 수, 재고 부족 등으로 거절된 주문 수, DLQ 전송 수, 알 수 없는 타입 수 등 처리 통계
+func (c *KafkaConsumer) Stats() ConsumerStats {
+    return ConsumerStats{
+        DuplicatesSkipped: c.duplicatesSkipped.Load(),
+        OrdersRejected:    c.ordersRejected.Load(),
+        DeadLettered:      c.deadLettered.Load(),
+        UnknownSkipped:    c.unknownSkipped.Load(),
+    }
+}
+
+// Stop - 새 메시지 읽기를 멈추고 처리 중인 메시지가 끝나 커밋될 때까지 대기
+// 재시도 대기 중이던 메시지는 커밋하지 않으므로 재시작 후 다시 전달된다.
 func (c *KafkaConsumer) Stop() {
     c.logger.Info("Stopping Kafka consumer")
     if c.cancel != nil {
         c.cancel()
     }
+    c.wg.Wait()
 }
 
-func (c *KafkaConsumer) StartConsuming(ctx context.Context) {
-    c.logger.Info("Starting Kafka consumer")
-    
-    for {
+// Start - 읽기 루프를 고루틴으로 시작 (Stop이 루프 종료를 기다릴 수 있도록 고루틴 시작 전에 등록)
+func (c *KafkaConsumer) Start(ctx context.Context) {
+    c.wg.Add(1)
+    go func() {
+        defer c.wg.Done()
+        c.consume(ctx)
+    }()
+}
+
+// consume - 메시지를 읽어 워커 풀에 전달 (Stop 또는 ctx 취소 시 종료)
+func (c *KafkaConsumer) consume(ctx context.Context) {
+    c.logger.Info("Starting Kafka consumer",
+        zap.Strings("brokers", c.cfg.Brokers),
+        zap.String("topic", c.cfg.Topic),
+        zap.String("group_id", c.cfg.GroupID),
+        zap.Int64("start_offset", c.cfg.StartOffset),
+        zap.Int("min_bytes", c.cfg.MinBytes),
+        zap.Int("max_bytes", c.cfg.MaxBytes),
+        zap.Duration("commit_interval", c.cfg.CommitInterval),
+        zap.String("dlq_topic", c.cfg.DLQTopic),
+        zap.Int("retry_max_attempts", c.cfg.Retry.MaxAttempts),
+        zap.Int("workers", c.cfg.Workers),
+        zap.String("ordering", c.cfg.Ordering))
+
+    c.running.Store(true)
+    defer c.running.Store(false)
+
+    // 상위 ctx 취소도 Stop과 같이 읽기만 중단
+    go func() {
         select {
         case <-ctx.Done():
-            c.logger.Info("Context cancelled, stopping consumer")
-            return
+            c.cancel()
         case <-c.ctx.Done():
-            c.logger.Info("Consumer stopped")
-            return
-        default:
-            msg, err := c.reader.ReadMessage(ctx)
-            if err != nil {
-                if err == context.Canceled {
-                    return
-                }
-                c.logger.Error("Failed to read message", zap.Error(err))
-                continue
-            }
-            
-            var event OrderCreatedEvent
-            if err := json.Unmarshal(msg.Value, &event); err != nil {
-                c.logger.Error("Failed to unmarshal event", zap.Error(err))
-                continue
-            }
-            
-            c.logger.Info("Processing order event",
-                zap.String("event_id", event.EventID),
-                zap.Int("order_id", event.OrderID),
-                zap.Int("items_count", len(event.Items)))
-            
-            // 각 상품의 재고 차감
-            for _, item := range event.Items {
-                // int를 string으로 변환!
-                productIDStr := strconv.Itoa(item.ProductID)
-                
-                result, err := c.productService.DeductStock(ctx, productIDStr, item.Quantity)
-                if err != nil {
-                    c.logger.Error("Failed to deduct stock",
-                        zap.String("product_id", productIDStr),  // 변환된 string 사용
-                        zap.Int("quantity", item.Quantity),
-                        zap.Error(err))
-                    continue
-                }
-                
-                c.logger.Info("Stock deducted successfully",
-                    zap.String("product_id", productIDStr),  // 변환된 string 사용
-                    zap.Int("previous_stock", result.PreviousStock),
-                    zap.Int("new_stock", result.NewStock),
-                    zap.Int("deducted", result.Deducted))
+        }
+    }()
+    // 처리와 커밋은 중단 신호와 무관하게 끝까지 진행
+    processCtx := context.WithoutCancel(ctx)
+
+    // 오프셋은 처리(또는 DLQ 전송)가 끝난 뒤 파티션별 순서대로만 커밋
+    pool := newWorkerPool(c, processCtx, c.cfg.Workers)
+    defer pool.close()
+
+    for {
+        msg, err := c.reader.FetchMessage(c.ctx)
+        if err != nil {
+            if c.ctx.Err() != nil {
+                logging.FromContext(ctx, c.logger).Info("Consumer stopped")
+                return
             }
+            c.readerError("failed to fetch message: %v", err)
+            logging.FromContext(ctx, c.logger).Error("Failed to fetch message", zap.Error(err))
+            continue
+        }
+        c.lastFetchAt.Store(time.Now().UnixNano())
+        metrics.KafkaConsumerLag.WithLabelValues(msg.Topic, strconv.Itoa(msg.Partition)).Set(float64(msg.HighWaterMark - msg.Offset - 1))
+
+        if !pool.dispatch(msg) {
+            logging.FromContext(ctx, c.logger).Info("Consumer stopped")
+            return
         }
     }
 }
 
+// commit - 처리 완료된 메시지의 오프셋 커밋 (실패하면 재전달되지만 중복 처리 방지로 안전)
+func (c *KafkaConsumer) commit(ctx context.Context, msg kafka.Message) {
+    ctx = logging.WithRequestID(ctx, headerValue(msg.Headers, HeaderRequestID))
+    ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+    defer cancel()
+
+    if err := c.reader.CommitMessages(ctx, msg); err != nil {
+        logging.FromContext(ctx, c.logger).Error("Failed to commit message",
+            zap.Int("partition", msg.Partition),
+            zap.Int64("offset", msg.Offset),
+            zap.Error(err))
+    }
+}
+
+// handleMessage - envelope의 type으로 등록된 처리 함수를 찾아 실행
+// 등록되지 않은 타입은 건너뛰고, 역직렬화할 수 없는 메시지는 DLQ로 전송한다.
+// 메시지 처리가 끝나 커밋해도 되면 true (중단되어 재전달이 필요하면 false)
+func (c *KafkaConsumer) handleMessage(ctx context.Context, msg kafka.Message) bool {
+    start := time.Now()
+    eventType := "malformed"
+    defer func() {
+        metrics.KafkaProcessingDuration.WithLabelValues(eventType).Observe(time.Since(start).Seconds())
+    }()
+    ctx, span := startConsumerSpan(ctx, msg)
+    defer span.End()
+    // 이벤트 본문의 request_id가 있으면 처리 함수에서 덮어쓴다
+    ctx = logging.WithRequestID(ctx, headerValue(msg.Headers, HeaderRequestID))
+
+    envelope, err := decodeMessage(msg)
+    if err != nil {
+        return c.poison(ctx, msg, err)
+    }
+    span.SetAttributes(
+        attribute.String("event.type", envelope.Type),
+        attribute.Int("event.version", envelope.Version),
+    )
+
+    handler, ok := c.registry.Lookup(envelope.Type)
+    if !ok {
+        eventType = "unknown"
+        c.unknownSkipped.Add(1)
+        metrics.IncUnknownEvent(envelope.Type)
+        logging.FromContext(ctx, c.logger).Warn("Skipping event with unknown type",
+            zap.String("type", envelope.Type),
+            zap.Int("version", envelope.Version),
+            zap.String("event_id", envelope.ID),
+            zap.Int64("unknown_types_skipped", c.unknownSkipped.Load()))
+        return true
+    }
+
+    eventType = envelope.Type
+    return c.processWithRetry(ctx, msg, envelope, handler)
+}
+
+func (c *KafkaConsumer) poison(ctx context.Context, msg kafka.Message, err error) bool {
+    logging.FromContext(ctx, c.logger).Error("Failed to unmarshal event",
+        zap.Int("partition", msg.Partition),
+        zap.Int64("offset", msg.Offset),
+        zap.Error(err))
+    tracing.RecordError(trace.SpanFromContext(ctx), err)
+    return c.deadLetter(ctx, msg, DLQReasonPoison, err, 0)
+}
+
+// processWithRetry - 일시적 오류는 재시도 정책에 따라 재시도하고, 재시도 초과 메시지는 DLQ로 전송
+// 재고 부족 등 영구 실패는 서비스가 실패 응답을 기록했으므로 처리 완료로 취급한다.
+func (c *KafkaConsumer) processWithRetry(ctx context.Context, msg kafka.Message, envelope *Envelope, handler HandlerFunc) bool {
+    logger := logging.FromContext(ctx, c.logger)
+    for attempt := 1; ; attempt++ {
+        err := handler(ctx, envelope)
+        if err == nil {
+            return true
+        }
+        trace.SpanFromContext(ctx).RecordError(err, trace.WithAttributes(attribute.Int("attempt", attempt)))
+        if errors.Is(err, errMalformedEvent) {
+            return c.poison(ctx, msg, err)
+        }
+
+        fields := []zap.Field{
+            zap.String("type", envelope.Type),
+            zap.String("event_id", envelope.ID),
+            zap.Int("attempt", attempt),
+            zap.Error(err),
+        }
+        var itemErr *domain.StockItemError
+        if errors.As(err, &itemErr) {
+            fields = append(fields, zap.String("product_id", itemErr.ProductID))
+        }
+
+        if isPermanent(err) {
+            c.ordersRejected.Add(1)
+            logger.Warn("Order rejected, failure reply recorded", fields...)
+            return true
+        }
+        if attempt >= c.cfg.Retry.MaxAttempts {
+            logger.Error("Giving up on event after retries", fields...)
+            trace.SpanFromContext(ctx).SetStatus(codes.Error, "retries exhausted")
+            return c.deadLetter(ctx, msg, DLQReasonExhausted, err, attempt)
+        }
+
+        backoff := c.cfg.Retry.Backoff(attempt)
+        logger.Warn("Failed to process event, will retry",
+            append(fields, zap.Duration("backoff", backoff))...)
+        if !sleepContext(c.ctx, backoff) {
+            return false
+        }
+    }
+}
+
+// deadLetter - DLQ로 전송 (DLQ 발행 실패 시 메시지를 잃지 않도록 중단 전까지 재시도, 전송했으면 true)
+func (c *KafkaConsumer) deadLetter(ctx context.Context, msg kafka.Message, reason string, cause error, attempts int) bool {
+    logger := logging.FromContext(ctx, c.logger)
+    dlqMsg := newDeadLetter(c.cfg.DLQTopic, msg, reason, cause, attempts)
+
+    for attempt := 1; ; attempt++ {
+        err := c.deadLetters.PublishMessage(ctx, dlqMsg)
+        if err == nil {
+            break
+        }
+        logger.Error("Failed to publish message to DLQ, will retry",
+            zap.String("dlq_topic", c.cfg.DLQTopic),
+            zap.Int("attempt", attempt),
+            zap.Error(err))
+        if !sleepContext(c.ctx, c.cfg.Retry.Backoff(attempt)) {
+            return false
+        }
+    }
+
+    c.deadLettered.Add(1)
+    logger.Warn("Message sent to DLQ",
+        zap.String("dlq_topic", c.cfg.DLQTopic),
+        zap.String("reason", reason),
+        zap.Int("partition", msg.Partition),
+        zap.Int64("offset", msg.Offset),
+        zap.Int("attempts", attempts),
+        zap.Error(cause))
+    return true
+}
+
+// sleepContext - d만큼 대기 (ctx가 먼저 취소되면 false)
+func sleepContext(ctx context.Context, d time.Duration) bool {
+    timer := time.NewTimer(d)
+    defer timer.Stop()
+
+    select {
+    case <-ctx.Done():
+        return false
+    case <-timer.C:
+        return true
+    }
+}
+
 func (c *KafkaConsumer) Close() error {
     c.Stop()
     if c.reader != nil {
         return c.reader.Close()
     }
     return nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,156 @@
+package events
+
+import (
+    "context"
+    "errors"
+    "strconv"
+    "strings"
+    "time"
+
+    "github.com/cloud-wave-best-zizon/product-service/pkg/logging"
+    "github.com/segmentio/kafka-go"
+    "go.uber.org/zap"
+)
+
+// DLQ 메시지에 붙는 오류 메타데이터 헤더
+const (
+    HeaderDLQError             = "x-dlq-error"
+    HeaderDLQReason            = "x-dlq-reason"
+    HeaderDLQOriginalTopic     = "x-dlq-original-topic"
+    HeaderDLQOriginalPartition = "x-dlq-original-partition"
+    HeaderDLQOriginalOffset    = "x-dlq-original-offset"
+    HeaderDLQAttempts          = "x-dlq-attempts"
+    HeaderDLQFailedAt          = "x-dlq-failed-at"
+    HeaderDLQReplayedAt        = "x-dlq-replayed-at"
+
+    dlqHeaderPrefix = "x-dlq-"
+)
+
+// DLQ 전송 사유
+const (
+    DLQReasonPoison    = "poison"            // 역직렬화 불가 메시지
+    DLQReasonExhausted = "retries_exhausted" // 재시도 횟수 초과
+)
+
+// MessagePublisher - 토픽/키/헤더가 지정된 메시지를 그대로 발행 (DLQ 전송, 재처리)
+type MessagePublisher interface {
+    PublishMessage(ctx context.Context, msg kafka.Message) error
+}
+
+// newDeadLetter - 원본 메시지의 키/값/헤더를 유지하고 오류 메타데이터 헤더를 추가한 DLQ 메시지
+func newDeadLetter(dlqTopic string, msg kafka.Message, reason string, cause error, attempts int) kafka.Message {
+    headers := withoutDLQHeaders(msg.Headers)
+    headers = append(headers,
+        kafka.Header{Key: HeaderDLQError, Value: []byte(cause.Error())},
+        kafka.Header{Key: HeaderDLQReason, Value: []byte(reason)},
+        kafka.Header{Key: HeaderDLQOriginalTopic, Value: []byte(msg.Topic)},
+        kafka.Header{Key: HeaderDLQOriginalPartition, Value: []byte(strconv.Itoa(msg.Partition))},
+        kafka.Header{Key: HeaderDLQOriginalOffset, Value: []byte(strconv.FormatInt(msg.Offset, 10))},
+        kafka.Header{Key: HeaderDLQAttempts, Value: []byte(strconv.Itoa(attempts))},
+        kafka.Header{Key: HeaderDLQFailedAt, Value: []byte(time.Now().UTC().Format(time.RFC3339))},
+    )
+
+    return kafka.Message{
+        Topic:   dlqTopic,
+        Key:     msg.Key,
+        Value:   msg.Value,
+        Headers: headers,
+    }
+}
+
+func withoutDLQHeaders(headers []kafka.Header) []kafka.Header {
+    kept := make([]kafka.Header, 0, len(headers)+8)
+    for _, h := range headers {
+        if !strings.HasPrefix(h.Key, dlqHeaderPrefix) {
+            kept = append(kept, h)
+        }
+    }
+    return kept
+}
+
+func headerValue(headers []kafka.Header, key string) string {
+    for _, h := range headers {
+        if h.Key == key {
+            return string(h.Value)
+        }
+    }
+    return ""
+}
+
+type DLQReplayerConfig struct {
+    Brokers      []string
+    DLQTopic     string
+    GroupID      string
+    DefaultTopic string        // 원본 토픽 헤더가 없을 때 재발행할 토픽
+    IdleTimeout  time.Duration // 이 시간 동안 새 메시지가 없으면 재처리 종료
+    Security     KafkaSecurity
+}
+
+// DLQReplayer - DLQ 메시지를 원래 토픽으로 재발행
+// 별도 컨슈머 그룹으로 읽고 재발행한 메시지만 커밋하므로 여러 번 나눠 실행할 수 있다.
+type DLQReplayer struct {
+    cfg       DLQReplayerConfig
+    publisher MessagePublisher
+    logger    *zap.Logger
+}
+
+func NewDLQReplayer(cfg DLQReplayerConfig, publisher MessagePublisher, logger *zap.Logger) *DLQReplayer {
+    return &DLQReplayer{
+        cfg:       cfg,
+        publisher: publisher,
+        logger:    logger,
+    }
+}
+
+// Replay - 최대 limit개의 DLQ 메시지를 재발행하고 재발행한 수를 반환
+func (r *DLQReplayer) Replay(ctx context.Context, limit int) (int, error) {
+    reader := kafka.NewReader(kafka.ReaderConfig{
+        Brokers:     r.cfg.Brokers,
+        Topic:       r.cfg.DLQTopic,
+        GroupID:     r.cfg.GroupID,
+        StartOffset: kafka.FirstOffset,
+        Dialer:      r.cfg.Security.dialer(),
+    })
+    defer reader.Close()
+
+    replayed := 0
+    for replayed < limit {
+        fetchCtx, cancel := context.WithTimeout(ctx, r.cfg.IdleTimeout)
+        msg, err := reader.FetchMessage(fetchCtx)
+        cancel()
+        if err != nil {
+            // 대기 시간 내에 메시지가 없으면 DLQ를 모두 비운 것으로 판단
+            if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+                break
+            }
+            return replayed, err
+        }
+
+        topic := headerValue(msg.Headers, HeaderDLQOriginalTopic)
+        if topic == "" {
+            topic = r.cfg.DefaultTopic
+        }
+        headers := append(withoutDLQHeaders(msg.Headers),
+            kafka.Header{Key: HeaderDLQReplayedAt, Value: []byte(time.Now().UTC().Format(time.RFC3339))})
+
+        if err := r.publisher.PublishMessage(ctx, kafka.Message{
+            Topic:   topic,
+            Key:     msg.Key,
+            Value:   msg.Value,
+            Headers: headers,
+        }); err != nil {
+            return replayed, err
+        }
+        if err := reader.CommitMessages(ctx, msg); err != nil {
+            return replayed, err
+        }
+        replayed++
+
+        logging.FromContext(ctx, r.logger).Info("Replayed dead-lettered message",
+            zap.String("topic", topic),
+            zap.Int64("dlq_offset", msg.Offset),
+            zap.String("error", headerValue(msg.Headers, HeaderDLQError)))
+    }
+
+    return replayed, nil
+}
@@ -0,0 +1,125 @@
+package events
+
+import (
+    "encoding/json"
+    "errors"
+    "fmt"
+    "strconv"
+    "time"
+
+    "github.com/segmentio/kafka-go"
+)
+
+// 주문 이벤트 타입 (envelope의 type)
+const (
+    EventTypeOrderCreated = "OrderCreated"
+)
+
+// errMalformedEvent - 역직렬화/검증에 실패한 메시지 (재시도하지 않고 DLQ로 전송)
+var errMalformedEvent = errors.New("malformed event")
+
+// Envelope - 버전이 있는 이벤트 공통 포맷 (payload는 type/version별 이벤트 본문)
+type Envelope struct {
+    Type    string          `json:"type"`
+    Version int             `json:"version"`
+    ID      string          `json:"id"`
+    Time    time.Time       `json:"time"`
+    Payload json.RawMessage `json:"payload"`
+
+    codec Codec // payload 인코딩 (nil이면 JSON)
+}
+
+func NewEnvelope(eventType string, version int, id string, t time.Time, payload interface{}) (*Envelope, error) {
+    body, err := json.Marshal(payload)
+    if err != nil {
+        return nil, err
+    }
+    return &Envelope{
+        Type:    eventType,
+        Version: version,
+        ID:      id,
+        Time:    t,
+        Payload: body,
+    }, nil
+}
+
+// DecodePayload - payload를 메시지의 코덱으로 v에 역직렬화 (실패하면 errMalformedEvent)
+func (e *Envelope) DecodePayload(v interface{}) error {
+    codec := e.codec
+    if codec == nil {
+        codec = JSONCodec
+    }
+    if err := codec.Unmarshal(e.Payload, v); err != nil {
+        if errors.Is(err, errMalformedEvent) {
+            return err
+        }
+        return fmt.Errorf("%w: %s v%d %s payload: %v", errMalformedEvent, e.Type, e.Version, codec.ContentType(), err)
+    }
+    return nil
+}
+
+// decodeMessage - content-type 헤더로 코덱을 골라 envelope 구성
+// JSON은 본문의 envelope(또는 기존 형식), Protobuf는 x-event-type/x-schema-version 헤더와 이벤트 본문을 사용한다.
+func decodeMessage(msg kafka.Message) (*Envelope, error) {
+    codec, err := codecFromHeaders(msg.Headers)
+    if err != nil {
+        return nil, err
+    }
+
+    if codec == JSONCodec {
+        return decodeEnvelope(msg.Value)
+    }
+    return headerEnvelope(msg, codec)
+}
+
+// headerEnvelope - 타입/버전을 헤더로 전달하는 바이너리 인코딩 메시지
+func headerEnvelope(msg kafka.Message, codec Codec) (*Envelope, error) {
+    eventType := headerValue(msg.Headers, HeaderEventType)
+    if eventType == "" {
+        return nil, fmt.Errorf("%w: %s message without %s header", errMalformedEvent, codec.ContentType(), HeaderEventType)
+    }
+    version, err := strconv.Atoi(headerValue(msg.Headers, HeaderSchemaVersion))
+    if err != nil {
+        return nil, fmt.Errorf("%w: %s message without valid %s header", errMalformedEvent, codec.ContentType(), HeaderSchemaVersion)
+    }
+    return &Envelope{
+        Type:    eventType,
+        Version: version,
+        Time:    msg.Time,
+        Payload: msg.Value,
+        codec:   codec,
+    }, nil
+}
+
+// decodeEnvelope - envelope로 감싸지 않은 기존 메시지는 event_type(없으면 OrderCreated) v1 이벤트로 취급
+func decodeEnvelope(value []byte) (*Envelope, error) {
+    var envelope Envelope
+    if err := json.Unmarshal(value, &envelope); err != nil {
+        return nil, fmt.Errorf("%w: %v", errMalformedEvent, err)
+    }
+    if envelope.Type != "" {
+        if len(envelope.Payload) == 0 {
+            return nil, fmt.Errorf("%w: %s envelope without payload", errMalformedEvent, envelope.Type)
+        }
+        return &envelope, nil
+    }
+
+    var legacy struct {
+        EventID   string    `json:"event_id"`
+        EventType string    `json:"event_type"`
+        Timestamp time.Time `json:"timestamp"`
+    }
+    if err := json.Unmarshal(value, &legacy); err != nil {
+        return nil, fmt.Errorf("%w: %v", errMalformedEvent, err)
+    }
+    if legacy.EventType == "" {
+        legacy.EventType = EventTypeOrderCreated
+    }
+    return &Envelope{
+        Type:    legacy.EventType,
+        Version: 1,
+        ID:      legacy.EventID,
+        Time:    legacy.Timestamp,
+        Payload: value,
+    }, nil
+}
@@ -2,6 +2,8 @@ package events
 
 import (
     "time"
+
+    "github.com/cloud-wave-best-zizon/product-service/internal/domain"
 )
 
 // Order Service에서 받을 이벤트
@@ -16,11 +18,25 @@ type OrderCreatedEvent struct {
     RequestID   string      `json:"request_id"`  
 }
 
+// 주문 취소/환불 이벤트 (event_type으로 OrderCreatedEvent와 구분)
+// 복구 수량은 이벤트가 아니라 이 주문에서 실제로 차감한 기록을 따른다.
+type OrderCancelledEvent struct {
+    EventID   string    `json:"event_id"`
+    EventType string    `json:"event_type"`
+    OrderID   int       `json:"order_id"`
+    Reason    string    `json:"reason"`
+    Timestamp time.Time `json:"timestamp"`
+    RequestID string    `json:"request_id"`
+}
+
+const EventTypeOrderCancelled = "OrderCancelled"
+
+// OrderItem - product_id는 문자열("P-100")과 정수(42) 모두 허용
 type OrderItem struct {
-    ProductID   int  `json:"product_id"`   
-    ProductName string  `json:"product_name"` 
-    Quantity    int     `json:"quantity"`     
-    Price       float64 `json:"price"`        
+    ProductID   domain.ProductID `json:"product_id"`
+    ProductName string           `json:"product_name"`
+    Quantity    int              `json:"quantity"`
+    Price       float64          `json:"price"`
 }
 
 // 재고 차감 완료 이벤트
@@ -31,4 +47,67 @@ type StockDeductedEvent struct {
     Quantity    int       `json:"quantity"`  
     NewStock    int       `json:"new_stock"` 
     Timestamp   time.Time `json:"timestamp"` 
-}
\ No newline at end of file
+    RequestID   string    `json:"request_id"`
+}
+
+// EventTypeStockDeducted - 페이로드에는 event_type이 없고 x-event-type 헤더로만 전달 (기존 소비자 호환)
+const EventTypeStockDeducted = "StockDeducted"
+
+// 주문 취소로 재고 복구 완료 이벤트 (stock 토픽, StockDeductedEvent와 event_type으로 구분)
+type StockRestockedEvent struct {
+    EventID   string    `json:"event_id"`
+    EventType string    `json:"event_type"`
+    OrderID   int       `json:"order_id"`
+    ProductID string    `json:"product_id"`
+    Quantity  int       `json:"quantity"`
+    NewStock  int       `json:"new_stock"`
+    Timestamp time.Time `json:"timestamp"`
+    RequestID string    `json:"request_id"`
+}
+
+const EventTypeStockRestocked = "StockRestocked"
+
+// 사가 응답 이벤트 타입 (order-service가 주문 확정/취소를 결정)
+const (
+    EventTypeStockReserved          = "StockReserved"
+    EventTypeStockReservationFailed = "StockReservationFailed"
+)
+
+// 재고 차감 실패 사유
+const (
+    ReasonInsufficientStock = "insufficient_stock"
+    ReasonProductNotFound   = "product_not_found"
+    ReasonInvalidRequest    = "invalid_request"
+)
+
+// 주문 재고 차감 성공 응답
+type StockReservedEvent struct {
+    EventID     string              `json:"event_id"`
+    EventType   string              `json:"event_type"`
+    OrderID     int                 `json:"order_id"`
+    CausationID string              `json:"causation_id"` // 원인 OrderCreatedEvent의 event_id
+    Items       []StockReservedItem `json:"items"`
+    Timestamp   time.Time           `json:"timestamp"`
+    RequestID   string              `json:"request_id"`
+}
+
+type StockReservedItem struct {
+    ProductID     string `json:"product_id"`
+    Quantity      int    `json:"quantity"`
+    PreviousStock int    `json:"previous_stock"`
+    NewStock      int    `json:"new_stock"`
+}
+
+// 주문 재고 차감 실패 응답 (재고는 변경되지 않음)
+type StockReservationFailedEvent struct {
+    EventID     string    `json:"event_id"`
+    EventType   string    `json:"event_type"`
+    OrderID     int       `json:"order_id"`
+    CausationID string    `json:"causation_id"`
+    Reason      string    `json:"reason"`
+    ProductID   string    `json:"product_id,omitempty"` // 실패한 상품
+    Available   int       `json:"available,omitempty"`  // 재고 부족 시 현재 재고
+    Message     string    `json:"message"`
+    Timestamp   time.Time `json:"timestamp"`
+    RequestID   string    `json:"request_id"`
+}
@@ -0,0 +1,106 @@
+package events
+
+import (
+    "context"
+    "fmt"
+    "sort"
+    "strconv"
+
+    "github.com/cloud-wave-best-zizon/product-service/pkg/tracing"
+    "github.com/segmentio/kafka-go"
+)
+
+// 발행되는 모든 메시지에 붙는 표준 헤더
+// trace context는 OpenTelemetry 전파기가 W3C 헤더(traceparent, tracestate)로 기록한다.
+const (
+    HeaderEventType     = "x-event-type"
+    HeaderSchemaVersion = "x-schema-version"
+    HeaderRequestID     = "x-request-id"
+)
+
+// KeyStrategy - 메시지 키(파티션)를 정하는 기준
+type KeyStrategy string
+
+const (
+    KeyByOrderID   KeyStrategy = "order_id"   // 같은 주문의 이벤트 순서 보장
+    KeyByProductID KeyStrategy = "product_id" // 같은 상품의 재고 변경 순서 보장
+    KeyByEventID   KeyStrategy = "event_id"   // 순서 보장 없이 고르게 분산
+)
+
+// 프로듀서 파티션 선택 방식
+const (
+    BalancerHash       = "hash"        // FNV-1a (kafka-go 기본 해시)
+    BalancerMurmur2    = "murmur2"     // Java 클라이언트 기본 파티셔너와 같은 파티션
+    BalancerLeastBytes = "least_bytes" // 키 무시, 순서 보장 없음
+)
+
+// ParseKeyStrategy - 설정값(order_id | product_id | event_id)을 KeyStrategy로 변환
+func ParseKeyStrategy(value string) (KeyStrategy, error) {
+    switch strategy := KeyStrategy(value); strategy {
+    case KeyByOrderID, KeyByProductID, KeyByEventID:
+        return strategy, nil
+    default:
+        return "", fmt.Errorf("invalid key strategy %q (expected order_id, product_id or event_id)", value)
+    }
+}
+
+// NewBalancer - 설정값(hash | murmur2 | least_bytes)에 맞는 파티션 선택기
+func NewBalancer(name string) (kafka.Balancer, error) {
+    switch name {
+    case BalancerHash:
+        return &kafka.Hash{}, nil
+    case BalancerMurmur2:
+        return kafka.Murmur2Balancer{}, nil
+    case BalancerLeastBytes:
+        return &kafka.LeastBytes{}, nil
+    default:
+        return nil, fmt.Errorf("invalid balancer %q (expected hash, murmur2 or least_bytes)", name)
+    }
+}
+
+// MessageKey - 전략에 맞는 메시지 키 (해당 값이 없으면 이벤트 ID로 대체)
+func MessageKey(strategy KeyStrategy, orderID int, productID, eventID string) string {
+    switch strategy {
+    case KeyByOrderID:
+        if orderID != 0 {
+            return strconv.Itoa(orderID)
+        }
+    case KeyByProductID:
+        if productID != "" {
+            return productID
+        }
+    }
+    return eventID
+}
+
+// MessageHeaders - 표준 헤더 맵 (값이 없는 헤더는 생략, trace context는 ctx의 현재 스팬)
+func MessageHeaders(ctx context.Context, eventType string, schemaVersion int, contentType, requestID string) map[string]string {
+    headers := map[string]string{
+        HeaderEventType:     eventType,
+        HeaderSchemaVersion: strconv.Itoa(schemaVersion),
+        HeaderContentType:   contentType,
+    }
+    if requestID != "" {
+        headers[HeaderRequestID] = requestID
+    }
+    tracing.Inject(ctx, headers)
+    return headers
+}
+
+// kafkaHeaders - 헤더 맵을 키 순서대로 kafka.Header로 변환
+func kafkaHeaders(headers map[string]string) []kafka.Header {
+    if len(headers) == 0 {
+        return nil
+    }
+    keys := make([]string, 0, len(headers))
+    for key := range headers {
+        keys = append(keys, key)
+    }
+    sort.Strings(keys)
+
+    result := make([]kafka.Header, 0, len(keys))
+    for _, key := range keys {
+        result = append(result, kafka.Header{Key: key, Value: []byte(headers[key])})
+    }
+    return result
+}
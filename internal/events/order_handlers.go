@@ -0,0 +1,121 @@
+package events
+
+import (
+    "context"
+    "errors"
+    "fmt"
+
+    "github.com/cloud-wave-best-zizon/product-service/internal/domain"
+    "github.com/cloud-wave-best-zizon/product-service/pkg/logging"
+    "go.uber.org/zap"
+)
+
+// handleOrderCreated - OrderCreated v1 처리
+func (c *KafkaConsumer) handleOrderCreated(ctx context.Context, envelope *Envelope) error {
+    if envelope.Version != 1 {
+        return fmt.Errorf("%w: unsupported %s version %d", errMalformedEvent, envelope.Type, envelope.Version)
+    }
+    var event OrderCreatedEvent
+    if err := envelope.DecodePayload(&event); err != nil {
+        return err
+    }
+    if event.EventID == "" {
+        event.EventID = envelope.ID
+    }
+    return c.processOrderCreated(ctx, event)
+}
+
+// handleOrderCancelled - OrderCancelled v1 처리
+func (c *KafkaConsumer) handleOrderCancelled(ctx context.Context, envelope *Envelope) error {
+    if envelope.Version != 1 {
+        return fmt.Errorf("%w: unsupported %s version %d", errMalformedEvent, envelope.Type, envelope.Version)
+    }
+    var event OrderCancelledEvent
+    if err := envelope.DecodePayload(&event); err != nil {
+        return err
+    }
+    if event.EventID == "" {
+        event.EventID = envelope.ID
+    }
+    if event.OrderID == 0 || event.EventID == "" {
+        return fmt.Errorf("%w: order cancelled event requires event_id and order_id", errMalformedEvent)
+    }
+    return c.processOrderCancelled(ctx, event)
+}
+
+// processOrderCreated - 주문의 모든 상품 재고를 한 번에 차감 (부분 차감 방지)
+// 이미 처리된 이벤트는 건너뛰고 성공으로 취급한다.
+func (c *KafkaConsumer) processOrderCreated(ctx context.Context, event OrderCreatedEvent) error {
+    ctx = logging.WithRequestID(ctx, event.RequestID)
+    logger := logging.FromContext(ctx, c.logger)
+    logger.Info("Processing order event",
+        zap.String("event_id", event.EventID),
+        zap.Int("order_id", event.OrderID),
+        zap.Int("items_count", len(event.Items)))
+
+    items := make([]domain.StockDeductionItem, 0, len(event.Items))
+    for _, item := range event.Items {
+        items = append(items, domain.StockDeductionItem{
+            ProductID: item.ProductID.String(),
+            Quantity:  item.Quantity,
+        })
+    }
+
+    origin := domain.StockOrigin{OrderID: event.OrderID, RequestID: event.RequestID, EventID: event.EventID}
+    results, err := c.productService.DeductStockForOrder(ctx, items, origin)
+    if errors.Is(err, domain.ErrDuplicateEvent) {
+        c.duplicatesSkipped.Add(1)
+        logger.Info("Skipping duplicate order event",
+            zap.String("event_id", event.EventID),
+            zap.Int("order_id", event.OrderID),
+            zap.Int64("duplicates_skipped", c.duplicatesSkipped.Load()))
+        return nil
+    }
+    if err != nil {
+        return err
+    }
+
+    for _, result := range results {
+        logger.Info("Stock deducted successfully",
+            zap.Int("order_id", event.OrderID),
+            zap.String("product_id", result.ProductID),
+            zap.Int("previous_stock", result.PreviousStock),
+            zap.Int("new_stock", result.NewStock),
+            zap.Int("deducted", result.Deducted))
+    }
+    return nil
+}
+
+// processOrderCancelled - 주문에서 실제로 차감했던 재고를 복구 (같은 주문은 한 번만)
+func (c *KafkaConsumer) processOrderCancelled(ctx context.Context, event OrderCancelledEvent) error {
+    ctx = logging.WithRequestID(ctx, event.RequestID)
+    logger := logging.FromContext(ctx, c.logger)
+    logger.Info("Processing order cancelled event",
+        zap.String("event_id", event.EventID),
+        zap.Int("order_id", event.OrderID),
+        zap.String("reason", event.Reason))
+
+    origin := domain.StockOrigin{OrderID: event.OrderID, RequestID: event.RequestID, EventID: event.EventID}
+    results, err := c.productService.RestockForOrder(ctx, origin)
+    if errors.Is(err, domain.ErrDuplicateEvent) {
+        c.duplicatesSkipped.Add(1)
+        logger.Info("Skipping duplicate order cancelled event",
+            zap.String("event_id", event.EventID),
+            zap.Int("order_id", event.OrderID),
+            zap.Int64("duplicates_skipped", c.duplicatesSkipped.Load()))
+        return nil
+    }
+    if err != nil {
+        return err
+    }
+
+    for _, result := range results {
+        logger.Info("Stock restocked successfully",
+            zap.Int("order_id", event.OrderID),
+            zap.String("product_id", result.ProductID),
+            zap.Int("previous_stock", result.PreviousStock),
+            zap.Int("new_stock", result.NewStock),
+            zap.Int("restocked", result.Restocked))
+    }
+    return nil
+}
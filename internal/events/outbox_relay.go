@@ -0,0 +1,171 @@
+package events
+
+import (
+    "context"
+    "sync"
+    "time"
+
+    "github.com/cloud-wave-best-zizon/product-service/internal/domain"
+    "github.com/cloud-wave-best-zizon/product-service/internal/repository"
+    "go.uber.org/zap"
+)
+
+// OutboxPublisher - 아웃박스 메시지를 브로커로 발행
+type OutboxPublisher interface {
+    PublishOutboxMessage(ctx context.Context, message *domain.OutboxMessage) error
+}
+
+type OutboxRelayConfig struct {
+    PollInterval time.Duration
+    BatchSize    int
+    RetryBase    time.Duration
+    RetryMax     time.Duration
+}
+
+// OutboxRelay - 아웃박스의 미발행 메시지를 생성 순으로 Kafka에 발행하고 완료 처리
+// 발행 실패 시 지수 백오프로 재시도하며, 같은 토픽/키의 뒤 메시지는 앞선 메시지가 발행될 때까지 대기한다.
+// 순서는 키(파티션) 단위로만 보장되므로 한 키가 막혀도 다른 키의 메시지는 계속 발행한다.
+type OutboxRelay struct {
+    store     repository.OutboxStore
+    publisher OutboxPublisher
+    cfg       OutboxRelayConfig
+    logger    *zap.Logger
+
+    mu  sync.RWMutex
+    lag domain.OutboxLag
+}
+
+func NewOutboxRelay(store repository.OutboxStore, publisher OutboxPublisher, cfg OutboxRelayConfig, logger *zap.Logger) *OutboxRelay {
+    return &OutboxRelay{
+        store:     store,
+        publisher: publisher,
+        cfg:       cfg,
+        logger:    logger,
+    }
+}
+
+// Lag - 마지막 폴링 기준 미발행 메시지 현황 (pending은 BatchSize까지만 집계)
+func (r *OutboxRelay) Lag() domain.OutboxLag {
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+    return r.lag
+}
+
+func (r *OutboxRelay) Run(ctx context.Context) {
+    r.logger.Info("Starting outbox relay",
+        zap.Duration("poll_interval", r.cfg.PollInterval),
+        zap.Int("batch_size", r.cfg.BatchSize))
+
+    ticker := time.NewTicker(r.cfg.PollInterval)
+    defer ticker.Stop()
+
+    for {
+        select {
+        case <-ctx.Done():
+            r.logger.Info("Outbox relay stopped")
+            return
+        case <-ticker.C:
+            r.relayOnce(ctx)
+        }
+    }
+}
+
+func (r *OutboxRelay) relayOnce(ctx context.Context) {
+    messages, err := r.store.ListPendingOutbox(ctx, r.cfg.BatchSize)
+    if err != nil {
+        if ctx.Err() == nil {
+            r.logger.Error("Failed to list pending outbox messages", zap.Error(err))
+            r.setLastError(err.Error())
+        }
+        return
+    }
+
+    // blocked - 이번 주기에 발행하지 못한 메시지가 있는 토픽/키 (뒤 메시지도 다음 주기로 넘김)
+    blocked := make(map[string]bool)
+    remaining := make([]*domain.OutboxMessage, 0)
+    lastError := ""
+    for _, message := range messages {
+        if ctx.Err() != nil {
+            return
+        }
+        orderingKey := message.Topic + "\x00" + message.Key
+        if blocked[orderingKey] {
+            remaining = append(remaining, message)
+            continue
+        }
+
+        now := time.Now()
+        // 재시도 대기 중이면 같은 키의 순서 보장을 위해 다음 주기로 넘김
+        if message.NextAttemptAt.After(now) {
+            lastError = message.LastError
+            blocked[orderingKey] = true
+            remaining = append(remaining, message)
+            continue
+        }
+
+        if err := r.publisher.PublishOutboxMessage(ctx, message); err != nil {
+            attempts := message.Attempts + 1
+            nextAttemptAt := now.Add(r.backoff(attempts))
+            lastError = err.Error()
+
+            r.logger.Warn("Failed to publish outbox message, will retry",
+                zap.String("message_id", message.MessageID),
+                zap.String("topic", message.Topic),
+                zap.Int("attempts", attempts),
+                zap.Time("next_attempt_at", nextAttemptAt),
+                zap.Error(err))
+
+            if err := r.store.MarkOutboxFailed(ctx, message.MessageID, attempts, nextAttemptAt, lastError); err != nil {
+                r.logger.Error("Failed to record outbox failure",
+                    zap.String("message_id", message.MessageID),
+                    zap.Error(err))
+            }
+            blocked[orderingKey] = true
+            remaining = append(remaining, message)
+            continue
+        }
+
+        // 완료 표시에 실패하면 다음 주기에 다시 발행됨 (at-least-once, 재발행 전까지 같은 키의 뒤 메시지는 대기)
+        if err := r.store.MarkOutboxSent(ctx, message.MessageID, now); err != nil {
+            r.logger.Error("Failed to mark outbox message as sent",
+                zap.String("message_id", message.MessageID),
+                zap.Error(err))
+            blocked[orderingKey] = true
+            remaining = append(remaining, message)
+        }
+    }
+
+    r.updateLag(remaining, lastError)
+}
+
+// backoff - 재시도 간격 (RetryBase * 2^(attempts-1), 최대 RetryMax)
+func (r *OutboxRelay) backoff(attempts int) time.Duration {
+    delay := r.cfg.RetryBase
+    for i := 1; i < attempts && delay < r.cfg.RetryMax; i++ {
+        delay *= 2
+    }
+    if delay > r.cfg.RetryMax {
+        delay = r.cfg.RetryMax
+    }
+    return delay
+}
+
+func (r *OutboxRelay) updateLag(pending []*domain.OutboxMessage, lastError string) {
+    lag := domain.OutboxLag{
+        Pending:   len(pending),
+        LastError: lastError,
+    }
+    if len(pending) > 0 {
+        lag.OldestAgeSeconds = time.Since(pending[0].CreatedAt).Seconds()
+    }
+
+    r.mu.Lock()
+    r.lag = lag
+    r.mu.Unlock()
+}
+
+func (r *OutboxRelay) setLastError(lastError string) {
+    r.mu.Lock()
+    r.lag.LastError = lastError
+    r.mu.Unlock()
+}
@@ -4,48 +4,92 @@ import (
     "context"
     "encoding/json"
     "time"
+
+    "github.com/cloud-wave-best-zizon/product-service/internal/domain"
+    "github.com/cloud-wave-best-zizon/product-service/pkg/tracing"
     "github.com/segmentio/kafka-go"
     "go.uber.org/zap"
 )
 
 type KafkaProducer struct {
-    writer *kafka.Writer
-    logger *zap.Logger
+    writer           *kafka.Writer
+    orderTopic       string
+    orderKeyStrategy KeyStrategy
+    codec            Codec
+    logger           *zap.Logger
 }
 
-func NewKafkaProducer(brokers string) (*KafkaProducer, error) {
-    logger, _ := zap.NewProduction()
-    
+// KafkaProducerConfig - 토픽은 메시지마다 지정 (OrderTopic/OrderKeyStrategy는 PublishOrderCreated용)
+// Balancer가 nil이면 키 해시(kafka.Hash)로 파티션을 정해 같은 키의 메시지 순서를 보장한다.
+type KafkaProducerConfig struct {
+    Brokers          []string
+    OrderTopic       string
+    OrderKeyStrategy KeyStrategy
+    Balancer         kafka.Balancer
+    Codec            Codec // PublishOrderCreated 인코딩 (nil이면 JSON envelope)
+    Security         KafkaSecurity
+}
+
+func NewKafkaProducer(cfg KafkaProducerConfig, logger *zap.Logger) (*KafkaProducer, error) {
+    balancer := cfg.Balancer
+    if balancer == nil {
+        balancer = &kafka.Hash{}
+    }
+    keyStrategy := cfg.OrderKeyStrategy
+    if keyStrategy == "" {
+        keyStrategy = KeyByOrderID
+    }
+    codec := cfg.Codec
+    if codec == nil {
+        codec = JSONCodec
+    }
+
     writer := &kafka.Writer{
-        Addr:     kafka.TCP(brokers),
-        Topic:    "order-events",
-        Balancer: &kafka.LeastBytes{},
+        Addr:         kafka.TCP(cfg.Brokers...),
+        Balancer:     balancer,
         BatchTimeout: 10 * time.Millisecond,
+        Transport:    cfg.Security.transport(),
     }
-    
+
     return &KafkaProducer{
-        writer: writer,
-        logger: logger,
+        writer:           writer,
+        orderTopic:       cfg.OrderTopic,
+        orderKeyStrategy: keyStrategy,
+        codec:            codec,
+        logger:           logger,
     }, nil
 }
 
-func (p *KafkaProducer) PublishOrderCreated(event OrderCreatedEvent) error {
-    eventBytes, err := json.Marshal(event)
+// PublishOrderCreated - OrderCreated v1 발행 (키는 OrderKeyStrategy, 기본 주문 ID)
+// JSON은 envelope로 감싸고, Protobuf는 이벤트 본문만 싣고 타입/버전은 헤더로 전달한다.
+func (p *KafkaProducer) PublishOrderCreated(ctx context.Context, event OrderCreatedEvent) error {
+    eventBytes, err := p.encodeOrderCreated(event)
     if err != nil {
         p.logger.Error("Failed to marshal event", zap.Error(err))
         return err
     }
-    
+
+    var productID string
+    if len(event.Items) == 1 {
+        productID = event.Items[0].ProductID.String()
+    }
+    key := MessageKey(p.orderKeyStrategy, event.OrderID, productID, event.EventID)
+    ctx, span := startProducerSpan(ctx, p.orderTopic, key)
+    defer span.End()
+
     msg := kafka.Message{
-        Key:   []byte(event.EventID),
-        Value: eventBytes,
+        Topic:   p.orderTopic,
+        Key:     []byte(key),
+        Value:   eventBytes,
+        Headers: kafkaHeaders(MessageHeaders(ctx, EventTypeOrderCreated, 1, p.codec.ContentType(), event.RequestID)),
     }
     
-    ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+    ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
     defer cancel()
     
     err = p.writer.WriteMessages(ctx, msg)
     if err != nil {
+        tracing.RecordError(span, err)
         p.logger.Error("Failed to publish message", 
             zap.String("event_id", event.EventID),
             zap.Error(err))
@@ -59,9 +103,62 @@ func (p *KafkaProducer) PublishOrderCreated(event OrderCreatedEvent) error {
     return nil
 }
 
+func (p *KafkaProducer) encodeOrderCreated(event OrderCreatedEvent) ([]byte, error) {
+    if p.codec != JSONCodec {
+        return p.codec.Marshal(event)
+    }
+    envelope, err := NewEnvelope(EventTypeOrderCreated, 1, event.EventID, event.Timestamp, event)
+    if err != nil {
+        return nil, err
+    }
+    return json.Marshal(envelope)
+}
+
+// PublishOutboxMessage - 아웃박스에 기록된 메시지를 기록된 토픽/키 그대로 발행
+// 발행 스팬은 메시지를 기록한 요청의 스팬(헤더의 trace context)을 부모로 한다.
+func (p *KafkaProducer) PublishOutboxMessage(ctx context.Context, message *domain.OutboxMessage) error {
+    ctx, span := startProducerSpan(tracing.Extract(ctx, message.Headers), message.Topic, message.Key)
+    defer span.End()
+
+    headers := make(map[string]string, len(message.Headers)+2)
+    for key, value := range message.Headers {
+        headers[key] = value
+    }
+    tracing.Inject(ctx, headers)
+
+    msg := kafka.Message{
+        Topic:   message.Topic,
+        Key:     []byte(message.Key),
+        Value:   message.Payload,
+        Headers: kafkaHeaders(headers),
+    }
+    
+    ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+    defer cancel()
+    
+    if err := p.writer.WriteMessages(ctx, msg); err != nil {
+        tracing.RecordError(span, err)
+        return err
+    }
+    
+    p.logger.Info("Outbox message published",
+        zap.String("message_id", message.MessageID),
+        zap.String("topic", message.Topic))
+    
+    return nil
+}
+
+// PublishMessage - 토픽/키/헤더가 지정된 메시지를 그대로 발행 (DLQ 전송, DLQ 재처리)
+func (p *KafkaProducer) PublishMessage(ctx context.Context, msg kafka.Message) error {
+    ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+    defer cancel()
+    
+    return p.writer.WriteMessages(ctx, msg)
+}
+
 func (p *KafkaProducer) Close() error {
     if p.writer != nil {
         return p.writer.Close()
     }
     return nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,36 @@
+package events
+
+import (
+    "context"
+    "sync"
+)
+
+// HandlerFunc - 이벤트 타입별 처리 함수 (envelope.Version으로 payload 버전을 구분)
+// errMalformedEvent를 감싼 에러는 DLQ로, 그 외 에러는 재시도 정책에 따라 처리된다.
+type HandlerFunc func(ctx context.Context, envelope *Envelope) error
+
+// HandlerRegistry - 이벤트 타입 -> 처리 함수
+type HandlerRegistry struct {
+    mu       sync.RWMutex
+    handlers map[string]HandlerFunc
+}
+
+func NewHandlerRegistry() *HandlerRegistry {
+    return &HandlerRegistry{
+        handlers: make(map[string]HandlerFunc),
+    }
+}
+
+// Register - 같은 타입을 다시 등록하면 교체
+func (r *HandlerRegistry) Register(eventType string, handler HandlerFunc) {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    r.handlers[eventType] = handler
+}
+
+func (r *HandlerRegistry) Lookup(eventType string) (HandlerFunc, bool) {
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+    handler, ok := r.handlers[eventType]
+    return handler, ok
+}
@@ -0,0 +1,34 @@
+package events
+
+import (
+    "errors"
+    "time"
+
+    "github.com/cloud-wave-best-zizon/product-service/internal/domain"
+)
+
+// RetryPolicy - 일시적 오류에 대한 컨슈머 재시도 정책
+type RetryPolicy struct {
+    MaxAttempts int           // 최초 처리를 포함한 최대 시도 횟수
+    BaseBackoff time.Duration // 첫 재시도 대기 시간
+    MaxBackoff  time.Duration
+}
+
+// Backoff - attempt번째 시도가 실패한 뒤 대기 시간 (BaseBackoff * 2^(attempt-1), 최대 MaxBackoff)
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+    delay := p.BaseBackoff
+    for i := 1; i < attempt && delay < p.MaxBackoff; i++ {
+        delay *= 2
+    }
+    if delay > p.MaxBackoff {
+        delay = p.MaxBackoff
+    }
+    return delay
+}
+
+// isPermanent - 재시도해도 결과가 같은 오류 (상품 없음, 재고 부족, 잘못된 요청 - 실패 응답으로 처리됨)
+// 그 외 저장소 오류, 버전 충돌 등은 일시적 오류로 보고 재시도한다.
+func isPermanent(err error) bool {
+    var itemErr *domain.StockItemError
+    return errors.As(err, &itemErr) || errors.Is(err, domain.ErrInvalidBatch)
+}
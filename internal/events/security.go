@@ -0,0 +1,102 @@
+package events
+
+import (
+    "context"
+    "crypto/tls"
+    "errors"
+    "fmt"
+    "strings"
+    "time"
+
+    "github.com/segmentio/kafka-go"
+    "github.com/segmentio/kafka-go/sasl"
+    "github.com/segmentio/kafka-go/sasl/plain"
+    "github.com/segmentio/kafka-go/sasl/scram"
+    "go.uber.org/zap"
+)
+
+// SASL 인증 방식 (KAFKA_SASL_MECHANISM)
+const (
+    SASLPlain       = "plain"
+    SASLScramSHA256 = "scram-sha-256"
+    SASLScramSHA512 = "scram-sha-512"
+)
+
+// KafkaSecurity - 브로커 연결 보안 설정 (둘 다 nil이면 평문)
+type KafkaSecurity struct {
+    TLS  *tls.Config
+    SASL sasl.Mechanism
+}
+
+// NewSASLMechanism - mechanism이 비어 있으면 SASL을 사용하지 않음 (nil)
+func NewSASLMechanism(mechanism, username, password string) (sasl.Mechanism, error) {
+    switch strings.ToLower(mechanism) {
+    case "":
+        return nil, nil
+    case SASLPlain:
+        return plain.Mechanism{Username: username, Password: password}, nil
+    case SASLScramSHA256:
+        return scram.Mechanism(scram.SHA256, username, password)
+    case SASLScramSHA512:
+        return scram.Mechanism(scram.SHA512, username, password)
+    }
+    return nil, fmt.Errorf("unsupported SASL mechanism %q", mechanism)
+}
+
+// LogConnection - 브로커 연결 보안 설정을 연결 시작 시 한 번 기록 (메시지별 로그에는 남기지 않음)
+func (s KafkaSecurity) LogConnection(logger *zap.Logger, brokers []string) {
+    fields := []zap.Field{
+        zap.Strings("brokers", brokers),
+        zap.Bool("tls", s.TLS != nil),
+        zap.Bool("sasl", s.SASL != nil),
+    }
+    if s.SASL != nil {
+        fields = append(fields, zap.String("sasl_mechanism", s.SASL.Name()))
+    }
+    logger.Info("Connecting to Kafka", fields...)
+}
+
+// dialer - 컨슈머(Reader)용 연결 설정
+func (s KafkaSecurity) dialer() *kafka.Dialer {
+    return &kafka.Dialer{
+        Timeout:       10 * time.Second,
+        DualStack:     true,
+        TLS:           s.TLS,
+        SASLMechanism: s.SASL,
+    }
+}
+
+// transport - 프로듀서(Writer)용 연결 설정
+func (s KafkaSecurity) transport() *kafka.Transport {
+    return &kafka.Transport{
+        TLS:  s.TLS,
+        SASL: s.SASL,
+    }
+}
+
+// PingBrokers - 브로커 중 하나라도 연결(TLS 핸드셰이크, SASL 인증 포함)되고 메타데이터를 조회할 수 있는지 확인
+func PingBrokers(ctx context.Context, brokers []string, security KafkaSecurity) error {
+    if len(brokers) == 0 {
+        return errors.New("no kafka brokers configured")
+    }
+
+    dialer := security.dialer()
+    var errs []error
+    for _, broker := range brokers {
+        conn, err := dialer.DialContext(ctx, "tcp", broker)
+        if err != nil {
+            errs = append(errs, fmt.Errorf("%s: %w", broker, err))
+            continue
+        }
+        if deadline, ok := ctx.Deadline(); ok {
+            conn.SetDeadline(deadline)
+        }
+        _, err = conn.Brokers()
+        conn.Close()
+        if err == nil {
+            return nil
+        }
+        errs = append(errs, fmt.Errorf("%s: %w", broker, err))
+    }
+    return fmt.Errorf("no kafka broker reachable: %w", errors.Join(errs...))
+}
@@ -0,0 +1,57 @@
+package events
+
+import (
+    "context"
+    "strconv"
+
+    "github.com/cloud-wave-best-zizon/product-service/pkg/tracing"
+    "github.com/segmentio/kafka-go"
+    "go.opentelemetry.io/otel"
+    "go.opentelemetry.io/otel/attribute"
+    "go.opentelemetry.io/otel/trace"
+)
+
+// startConsumerSpan - 메시지 헤더의 trace context를 이어 받아 처리 스팬 생성 (order-service 스팬의 자식)
+func startConsumerSpan(ctx context.Context, msg kafka.Message) (context.Context, trace.Span) {
+    ctx = otel.GetTextMapPropagator().Extract(ctx, headerCarrier(msg.Headers))
+    return tracing.Tracer().Start(ctx, msg.Topic+" process",
+        trace.WithSpanKind(trace.SpanKindConsumer),
+        trace.WithAttributes(
+            attribute.String("messaging.system", "kafka"),
+            attribute.String("messaging.operation.type", "process"),
+            attribute.String("messaging.destination.name", msg.Topic),
+            attribute.String("messaging.destination.partition.id", strconv.Itoa(msg.Partition)),
+            attribute.Int64("messaging.kafka.offset", msg.Offset),
+            attribute.String("messaging.kafka.message.key", string(msg.Key)),
+        ))
+}
+
+// startProducerSpan - 발행 스팬 생성 (헤더에는 이 스팬의 trace context를 주입)
+func startProducerSpan(ctx context.Context, topic, key string) (context.Context, trace.Span) {
+    return tracing.Tracer().Start(ctx, topic+" publish",
+        trace.WithSpanKind(trace.SpanKindProducer),
+        trace.WithAttributes(
+            attribute.String("messaging.system", "kafka"),
+            attribute.String("messaging.operation.type", "publish"),
+            attribute.String("messaging.destination.name", topic),
+            attribute.String("messaging.kafka.message.key", key),
+        ))
+}
+
+// headerCarrier - Kafka 메시지 헤더용 OpenTelemetry TextMapCarrier
+type headerCarrier []kafka.Header
+
+func (c headerCarrier) Get(key string) string {
+    return headerValue(c, key)
+}
+
+// Set - 추출 전용으로만 사용하므로 기록하지 않음 (발행 시에는 MessageHeaders 맵에 주입)
+func (c headerCarrier) Set(string, string) {}
+
+func (c headerCarrier) Keys() []string {
+    keys := make([]string, 0, len(c))
+    for _, h := range c {
+        keys = append(keys, h.Key)
+    }
+    return keys
+}
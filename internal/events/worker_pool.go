@@ -0,0 +1,181 @@
+package events
+
+import (
+    "context"
+    "hash/fnv"
+    "strconv"
+    "sync"
+
+    "github.com/segmentio/kafka-go"
+    "go.uber.org/zap"
+)
+
+// 메시지 처리 순서를 보장하는 단위
+const (
+    OrderingPartition = "partition" // 같은 파티션의 메시지는 같은 워커가 순서대로 처리
+    OrderingKey       = "key"       // 같은 키(상품/주문)의 메시지는 같은 워커가 순서대로 처리
+)
+
+// 워커별 대기 메시지 수 (가득 차면 읽기가 멈춰 백프레셔)
+const workerQueueSize = 64
+
+// workerPool - 메시지를 순서 보장 단위별로 워커에 분배하고, 파티션마다 처리 완료된 연속 구간까지만 오프셋 커밋
+type workerPool struct {
+    consumer  *KafkaConsumer
+    queues    []chan kafka.Message
+    completed chan kafka.Message
+    tracker   *offsetTracker
+    workers   sync.WaitGroup
+    committer sync.WaitGroup
+}
+
+func newWorkerPool(c *KafkaConsumer, ctx context.Context, size int) *workerPool {
+    if size < 1 {
+        size = 1
+    }
+
+    p := &workerPool{
+        consumer:  c,
+        queues:    make([]chan kafka.Message, size),
+        completed: make(chan kafka.Message, size*workerQueueSize),
+        tracker:   newOffsetTracker(),
+    }
+    for i := range p.queues {
+        p.queues[i] = make(chan kafka.Message, workerQueueSize)
+        p.workers.Add(1)
+        go p.work(ctx, p.queues[i])
+    }
+    p.committer.Add(1)
+    go p.commitLoop(ctx)
+    return p
+}
+
+// dispatch - 순서 보장 단위의 해시로 워커 선택 (큐가 가득 차면 대기, 중단되면 false)
+func (p *workerPool) dispatch(msg kafka.Message) bool {
+    p.tracker.track(msg)
+
+    queue := p.queues[p.workerIndex(msg)]
+    select {
+    case queue <- msg:
+        return true
+    case <-p.consumer.ctx.Done():
+        return false
+    }
+}
+
+func (p *workerPool) workerIndex(msg kafka.Message) int {
+    if len(p.queues) == 1 {
+        return 0
+    }
+
+    h := fnv.New32a()
+    if p.consumer.cfg.Ordering == OrderingKey && len(msg.Key) > 0 {
+        h.Write(msg.Key)
+    } else {
+        h.Write([]byte(msg.Topic))
+        h.Write([]byte(strconv.Itoa(msg.Partition)))
+    }
+    return int(h.Sum32() % uint32(len(p.queues)))
+}
+
+// work - 큐의 메시지를 순서대로 처리 (중단된 뒤 대기 중이던 메시지는 처리하지 않아 커밋되지 않음)
+func (p *workerPool) work(ctx context.Context, queue <-chan kafka.Message) {
+    defer p.workers.Done()
+
+    for msg := range queue {
+        if p.consumer.ctx.Err() != nil {
+            continue
+        }
+        if !p.consumer.handleMessage(ctx, msg) {
+            p.consumer.logger.Info("Consumer stopped before message was processed, it will be redelivered",
+                zap.Int("partition", msg.Partition),
+                zap.Int64("offset", msg.Offset))
+            continue
+        }
+        p.completed <- msg
+    }
+}
+
+// commitLoop - 처리 완료를 모아 파티션별로 앞선 메시지가 모두 끝난 오프셋까지만 커밋 (단일 고루틴이라 커밋 순서 보장)
+func (p *workerPool) commitLoop(ctx context.Context) {
+    defer p.committer.Done()
+
+    for msg := range p.completed {
+        if commit, ok := p.tracker.complete(msg); ok {
+            p.consumer.commit(ctx, commit)
+        }
+    }
+}
+
+// close - 큐를 닫고 처리 중인 메시지와 커밋이 끝날 때까지 대기
+func (p *workerPool) close() {
+    for _, queue := range p.queues {
+        close(queue)
+    }
+    p.workers.Wait()
+    close(p.completed)
+    p.committer.Wait()
+}
+
+type partitionKey struct {
+    topic     string
+    partition int
+}
+
+// offsetTracker - 파티션별로 읽은 순서와 처리 완료 여부를 추적
+type offsetTracker struct {
+    mu         sync.Mutex
+    partitions map[partitionKey]*partitionOffsets
+}
+
+type partitionOffsets struct {
+    pending []kafka.Message // 읽은 순서 (아직 커밋되지 않은 메시지)
+    done    map[int64]bool
+}
+
+func newOffsetTracker() *offsetTracker {
+    return &offsetTracker{
+        partitions: make(map[partitionKey]*partitionOffsets),
+    }
+}
+
+func (t *offsetTracker) track(msg kafka.Message) {
+    t.mu.Lock()
+    defer t.mu.Unlock()
+
+    key := partitionKey{topic: msg.Topic, partition: msg.Partition}
+    offsets, ok := t.partitions[key]
+    if !ok {
+        offsets = &partitionOffsets{done: make(map[int64]bool)}
+        t.partitions[key] = offsets
+    }
+    offsets.pending = append(offsets.pending, msg)
+}
+
+// complete - msg를 완료 처리하고, 앞에서부터 연속으로 완료된 마지막 메시지를 반환 (커밋할 것이 없으면 false)
+func (t *offsetTracker) complete(msg kafka.Message) (kafka.Message, bool) {
+    t.mu.Lock()
+    defer t.mu.Unlock()
+
+    offsets, ok := t.partitions[partitionKey{topic: msg.Topic, partition: msg.Partition}]
+    if !ok {
+        return kafka.Message{}, false
+    }
+    offsets.done[msg.Offset] = true
+
+    var last kafka.Message
+    advanced := 0
+    for _, pending := range offsets.pending {
+        if !offsets.done[pending.Offset] {
+            break
+        }
+        delete(offsets.done, pending.Offset)
+        last = pending
+        advanced++
+    }
+    if advanced == 0 {
+        return kafka.Message{}, false
+    }
+    offsets.pending = offsets.pending[advanced:]
+    return last, true
+}
@@ -0,0 +1,127 @@
+package events
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "strconv"
+    "sync"
+    "testing"
+    "time"
+
+    "github.com/segmentio/kafka-go"
+    "go.uber.org/zap"
+)
+
+const (
+    benchEventType      = "BenchmarkEvent"
+    benchPartitions     = 8
+    benchKeys           = 64
+    benchHandlerLatency = 100 * time.Microsecond // DynamoDB 트랜잭션 한 번 정도의 처리 시간
+)
+
+// fakeReader - 미리 만든 메시지를 순서대로 돌려주고, 파티션마다 마지막 오프셋이 커밋되면 done을 닫는 Reader
+type fakeReader struct {
+    messages []kafka.Message
+    next     int
+
+    mu        sync.Mutex
+    last      map[int]int64 // 파티션별 마지막 오프셋
+    remaining int           // 마지막 오프셋이 아직 커밋되지 않은 파티션 수
+    done      chan struct{}
+}
+
+func newFakeReader(b *testing.B, n int) *fakeReader {
+    value, err := json.Marshal(&Envelope{Type: benchEventType, Version: 1, Payload: json.RawMessage(`{}`)})
+    if err != nil {
+        b.Fatal(err)
+    }
+
+    r := &fakeReader{
+        messages: make([]kafka.Message, 0, n),
+        last:     make(map[int]int64),
+        done:     make(chan struct{}),
+    }
+    for i := 0; i < n; i++ {
+        partition := i % benchPartitions
+        offset := int64(i / benchPartitions)
+        r.messages = append(r.messages, kafka.Message{
+            Topic:         "orders",
+            Partition:     partition,
+            Offset:        offset,
+            HighWaterMark: offset + 1,
+            Key:           []byte("product-" + strconv.Itoa(i%benchKeys)),
+            Value:         value,
+        })
+        r.last[partition] = offset
+    }
+    r.remaining = len(r.last)
+    if r.remaining == 0 {
+        close(r.done)
+    }
+    return r
+}
+
+func (r *fakeReader) FetchMessage(ctx context.Context) (kafka.Message, error) {
+    if r.next < len(r.messages) {
+        msg := r.messages[r.next]
+        r.next++
+        return msg, nil
+    }
+    <-ctx.Done()
+    return kafka.Message{}, ctx.Err()
+}
+
+func (r *fakeReader) CommitMessages(ctx context.Context, msgs ...kafka.Message) error {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    for _, msg := range msgs {
+        if last, ok := r.last[msg.Partition]; ok && msg.Offset == last {
+            delete(r.last, msg.Partition)
+            r.remaining--
+            if r.remaining == 0 {
+                close(r.done)
+            }
+        }
+    }
+    return nil
+}
+
+func (r *fakeReader) Close() error { return nil }
+
+// BenchmarkWorkerPool - 순서 보장 단위와 워커 수별로 메시지를 읽어 처리하고 마지막 오프셋까지 커밋하는 시간
+func BenchmarkWorkerPool(b *testing.B) {
+    for _, ordering := range []string{OrderingPartition, OrderingKey} {
+        for _, workers := range []int{1, 4, 16} {
+            b.Run(fmt.Sprintf("ordering=%s/workers=%d", ordering, workers), func(b *testing.B) {
+                benchmarkWorkerPool(b, ordering, workers)
+            })
+        }
+    }
+}
+
+func benchmarkWorkerPool(b *testing.B, ordering string, workers int) {
+    reader := newFakeReader(b, b.N)
+
+    ctx, cancel := context.WithCancel(context.Background())
+    c := &KafkaConsumer{
+        reader:   reader,
+        cfg:      KafkaConsumerConfig{Topic: "orders", Workers: workers, Ordering: ordering, Retry: RetryPolicy{MaxAttempts: 1}},
+        logger:   zap.NewNop(),
+        ctx:      ctx,
+        cancel:   cancel,
+        registry: NewHandlerRegistry(),
+    }
+    c.Handle(benchEventType, func(ctx context.Context, envelope *Envelope) error {
+        time.Sleep(benchHandlerLatency)
+        return nil
+    })
+
+    b.ResetTimer()
+    c.Start(context.Background())
+    <-reader.done
+    b.StopTimer()
+
+    c.Stop()
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/cloud-wave-best-zizon/product-service/internal/events"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultReplayLimit = 100
+	maxReplayLimit     = 1000
+)
+
+type DLQHandler struct {
+	replayer *events.DLQReplayer
+	logger   *zap.Logger
+}
+
+func NewDLQHandler(replayer *events.DLQReplayer, logger *zap.Logger) *DLQHandler {
+	return &DLQHandler{
+		replayer: replayer,
+		logger:   logger,
+	}
+}
+
+// ReplayDLQ - DLQ 메시지를 원래 토픽으로 재발행 (?limit=, 기본 100)
+func (h *DLQHandler) ReplayDLQ(c *gin.Context) {
+	limit := defaultReplayLimit
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > maxReplayLimit {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "limit must be between 1 and 1000",
+			})
+			return
+		}
+		limit = parsed
+	}
+
+	replayed, err := h.replayer.Replay(c.Request.Context(), limit)
+	if err != nil {
+		requestLogger(c, h.logger).Error("Failed to replay DLQ messages",
+			zap.Int("replayed", replayed),
+			zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":    "Failed to replay DLQ messages",
+			"replayed": replayed,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"replayed": replayed,
+	})
+}
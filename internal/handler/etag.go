@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+var (
+	errInvalidETag = errors.New("invalid entity tag")
+	// errNoStrongETag - If-Match 목록에 강한 비교로 일치할 수 있는 ETag가 없음 (항상 412)
+	errNoStrongETag = errors.New("no strong entity tag")
+)
+
+// etag - 상품 버전을 강한 ETag 값으로 변환 (예: "3")
+func etag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// parseIfMatch - If-Match 헤더의 ETag 목록(콤마 구분)에서 기대 버전들을 추출 (헤더가 없거나 "*"이면 nil)
+// 목록 중 하나라도 현재 버전과 같으면 조건을 만족한다 (RFC 9110).
+// If-Match는 강한 비교를 사용하므로 약한 ETag(W/)와 목록 안의 "*"는 어떤 버전과도 일치하지 않는 것으로 취급하고,
+// 일치할 수 있는 ETag가 하나도 없으면 errNoStrongETag를 반환한다 (형식 오류는 errInvalidETag).
+func parseIfMatch(header string) ([]int64, error) {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return nil, nil
+	}
+
+	var versions []int64
+	tags := 0
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		tags++
+		if tag == "*" {
+			continue
+		}
+		if weak, ok := strings.CutPrefix(tag, "W/"); ok {
+			if _, err := parseETag(weak); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		version, err := parseETag(tag)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+	if tags == 0 {
+		return nil, errInvalidETag
+	}
+	if len(versions) == 0 {
+		return nil, errNoStrongETag
+	}
+	return versions, nil
+}
+
+// matchesIfNoneMatch - If-None-Match 헤더가 현재 버전과 일치하는지 (약한 비교)
+func matchesIfNoneMatch(header string, version int64) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return true
+		}
+		v, err := parseETag(strings.TrimPrefix(tag, "W/"))
+		if err == nil && v == version {
+			return true
+		}
+	}
+	return false
+}
+
+func parseETag(tag string) (int64, error) {
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return 0, errInvalidETag
+	}
+	version, err := strconv.ParseInt(tag[1:len(tag)-1], 10, 64)
+	if err != nil {
+		return 0, errInvalidETag
+	}
+	return version, nil
+}
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/cloud-wave-best-zizon/product-service/pkg/health"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type HealthHandler struct {
+	checker *health.Checker
+	logger  *zap.Logger
+}
+
+func NewHealthHandler(checker *health.Checker, logger *zap.Logger) *HealthHandler {
+	return &HealthHandler{
+		checker: checker,
+		logger:  logger,
+	}
+}
+
+// Livez - 프로세스와 컨슈머 루프가 살아 있는지 확인 (실패 시 503, 재시작 대상)
+func (h *HealthHandler) Livez(c *gin.Context) {
+	h.respond(c, "liveness", h.checker.Live(c.Request.Context()))
+}
+
+// Readyz - DynamoDB, Kafka, 컨슈머, SVID 상태를 실제로 확인 (필수 컴포넌트 실패 시 503)
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	h.respond(c, "readiness", h.checker.Ready(c.Request.Context()))
+}
+
+func (h *HealthHandler) respond(c *gin.Context, probe string, report health.Report) {
+	if !report.Healthy() {
+		h.logger.Warn("Health check failed",
+			zap.String("probe", probe),
+			zap.Any("components", report.Components))
+		c.JSON(http.StatusServiceUnavailable, report)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type LogLevelHandler struct {
+	level  zap.AtomicLevel
+	logger *zap.Logger
+}
+
+func NewLogLevelHandler(level zap.AtomicLevel, logger *zap.Logger) *LogLevelHandler {
+	return &LogLevelHandler{
+		level:  level,
+		logger: logger,
+	}
+}
+
+type setLogLevelRequest struct {
+	Level string `json:"level" binding:"required"`
+}
+
+// GetLevel - 현재 로그 레벨 조회
+func (h *LogLevelHandler) GetLevel(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"level": h.level.String(),
+	})
+}
+
+// SetLevel - 재시작 없이 로그 레벨 변경 ({"level": "debug"})
+func (h *LogLevelHandler) SetLevel(c *gin.Context) {
+	var req setLogLevelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+		})
+		return
+	}
+
+	// dpanic 이상은 거의 모든 로그를 끄게 되므로 인증 없는 관리용 호출로는 허용하지 않음
+	level, err := zapcore.ParseLevel(req.Level)
+	if err != nil || level > zapcore.ErrorLevel {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "level must be one of debug, info, warn, error",
+		})
+		return
+	}
+
+	// 변경 기록(Warn)이 걸러지지 않도록 두 레벨 중 더 자세한 쪽이 적용된 동안 기록 (올릴 때는 변경 전, 내릴 때는 변경 후)
+	previous := h.level.Level()
+	if level > previous {
+		h.logChange(c, previous, level)
+		h.level.SetLevel(level)
+	} else {
+		h.level.SetLevel(level)
+		h.logChange(c, previous, level)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"level": level.String(),
+	})
+}
+
+func (h *LogLevelHandler) logChange(c *gin.Context, from, to zapcore.Level) {
+	requestLogger(c, h.logger).Warn("Log level changed",
+		zap.Stringer("from", from),
+		zap.Stringer("to", to),
+		zap.String("ip", c.ClientIP()))
+}
@@ -1,10 +1,15 @@
 package handler
 
 import (
+	"errors"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/cloud-wave-best-zizon/product-service/internal/domain"
 	"github.com/cloud-wave-best-zizon/product-service/internal/service"
+	"github.com/cloud-wave-best-zizon/product-service/pkg/logging"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
@@ -21,11 +26,16 @@ func NewProductHandler(productService *service.ProductService, logger *zap.Logge
 	}
 }
 
+// requestLogger - 요청 ID(request_id)를 붙인 로거
+func requestLogger(c *gin.Context, logger *zap.Logger) *zap.Logger {
+	return logging.FromContext(c.Request.Context(), logger)
+}
+
 func (h *ProductHandler) CreateProduct(c *gin.Context) {
 	var req domain.CreateProductRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		h.logger.Error("Invalid request", zap.Error(err))
+		requestLogger(c, h.logger).Error("Invalid request", zap.Error(err))
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error": "Invalid request format",
 		})
@@ -34,6 +44,13 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 
 	product, err := h.productService.CreateProduct(c.Request.Context(), req)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidProductID) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
 		if err == service.ErrProductExists {
 			c.JSON(http.StatusConflict, gin.H{
 				"error": "Product already exists",
@@ -41,7 +58,7 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 			return
 		}
 
-		h.logger.Error("Failed to create product",
+		requestLogger(c, h.logger).Error("Failed to create product",
 			zap.String("product_id", req.ProductID),
 			zap.Error(err))
 
@@ -51,18 +68,14 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 		return
 	}
 
-	response := domain.ProductResponse{
-		ProductID: product.ProductID,
-		Name:      product.Name,
-		Stock:     product.Stock,
-		Price:     product.Price,
-	}
-
-	c.JSON(http.StatusCreated, response)
+	c.JSON(http.StatusCreated, newProductResponse(product))
 }
 
 func (h *ProductHandler) GetProduct(c *gin.Context) {
-	productID := c.Param("id")
+	productID, ok := bindProductID(c)
+	if !ok {
+		return
+	}
 
 	product, err := h.productService.GetProduct(c.Request.Context(), productID)
 	if err != nil {
@@ -73,7 +86,7 @@ func (h *ProductHandler) GetProduct(c *gin.Context) {
 			return
 		}
 
-		h.logger.Error("Failed to get product",
+		requestLogger(c, h.logger).Error("Failed to get product",
 			zap.String("product_id", productID),
 			zap.Error(err))
 
@@ -83,55 +96,309 @@ func (h *ProductHandler) GetProduct(c *gin.Context) {
 		return
 	}
 
-	response := domain.ProductResponse{
-		ProductID: product.ProductID,
-		Name:      product.Name,
-		Stock:     product.Stock,
-		Price:     product.Price,
+	c.Header("ETag", etag(product.Version))
+	if matchesIfNoneMatch(c.GetHeader("If-None-Match"), product.Version) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.JSON(http.StatusOK, newProductResponse(product))
+}
+
+func (h *ProductHandler) ListProducts(c *gin.Context) {
+	limit := 0
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid limit",
+			})
+			return
+		}
+		limit = n
+	}
+
+	products, nextCursor, err := h.productService.ListProducts(c.Request.Context(), limit, c.Query("cursor"))
+	if err != nil {
+		if err == service.ErrInvalidCursor {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid cursor",
+			})
+			return
+		}
+
+		requestLogger(c, h.logger).Error("Failed to list products", zap.Error(err))
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to list products",
+		})
+		return
+	}
+
+	response := domain.ProductListResponse{
+		Items:      make([]domain.ProductResponse, 0, len(products)),
+		NextCursor: nextCursor,
+	}
+	for _, product := range products {
+		response.Items = append(response.Items, newProductResponse(product))
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
+func (h *ProductHandler) UpdateProduct(c *gin.Context) {
+	productID, ok := bindProductID(c)
+	if !ok {
+		return
+	}
+
+	var req domain.UpdateProductRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		requestLogger(c, h.logger).Error("Invalid request", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+		})
+		return
+	}
+
+	expectedVersions, ok := h.bindIfMatch(c)
+	if !ok {
+		return
+	}
+
+	product, err := h.productService.UpdateProduct(c.Request.Context(), productID, req, expectedVersions)
+	if err != nil {
+		h.handleWriteError(c, productID, "Failed to update product", err)
+		return
+	}
+
+	c.Header("ETag", etag(product.Version))
+	c.JSON(http.StatusOK, newProductResponse(product))
+}
+
+// PatchProduct - application/merge-patch+json 본문으로 일부 필드 갱신
+func (h *ProductHandler) PatchProduct(c *gin.Context) {
+	productID, ok := bindProductID(c)
+	if !ok {
+		return
+	}
+
+	patch, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		requestLogger(c, h.logger).Error("Failed to read request body", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+		})
+		return
+	}
+
+	expectedVersions, ok := h.bindIfMatch(c)
+	if !ok {
+		return
+	}
+
+	product, err := h.productService.PatchProduct(c.Request.Context(), productID, patch, expectedVersions)
+	if err != nil {
+		h.handleWriteError(c, productID, "Failed to patch product", err)
+		return
+	}
+
+	c.Header("ETag", etag(product.Version))
+	c.JSON(http.StatusOK, newProductResponse(product))
+}
+
+func (h *ProductHandler) DeleteProduct(c *gin.Context) {
+	productID, ok := bindProductID(c)
+	if !ok {
+		return
+	}
+
+	expectedVersions, ok := h.bindIfMatch(c)
+	if !ok {
+		return
+	}
+
+	if err := h.productService.DeleteProduct(c.Request.Context(), productID, expectedVersions); err != nil {
+		h.handleWriteError(c, productID, "Failed to delete product", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// bindProductID - 경로의 상품 ID에서 앞뒤 공백만 제거 (비어 있으면 400 응답 후 false 반환)
+// 형식 검증은 생성 시에만 하므로, 규칙이 생기기 전에 만든 상품도 조회/수정/삭제할 수 있다.
+func bindProductID(c *gin.Context) (string, bool) {
+	productID := strings.TrimSpace(c.Param("id"))
+	if productID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid product id: empty",
+		})
+		return "", false
+	}
+	return productID, true
+}
+
+// bindIfMatch - If-Match 헤더 파싱 (일치할 수 있는 ETag가 없으면 412, 형식이 잘못되면 400 응답 후 false 반환)
+func (h *ProductHandler) bindIfMatch(c *gin.Context) ([]int64, bool) {
+	expectedVersions, err := parseIfMatch(c.GetHeader("If-Match"))
+	if errors.Is(err, errNoStrongETag) {
+		c.JSON(http.StatusPreconditionFailed, gin.H{
+			"error": "Product version mismatch",
+		})
+		return nil, false
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid If-Match header",
+		})
+		return nil, false
+	}
+	return expectedVersions, true
+}
+
+func (h *ProductHandler) handleWriteError(c *gin.Context, productID, message string, err error) {
+	switch {
+	case errors.Is(err, service.ErrProductNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Product not found",
+		})
+	case errors.Is(err, service.ErrVersionConflict):
+		// If-Match 조건 불일치는 412, 동시 수정으로 인한 충돌은 409
+		status := http.StatusConflict
+		if c.GetHeader("If-Match") != "" {
+			status = http.StatusPreconditionFailed
+		}
+		c.JSON(status, gin.H{
+			"error": "Product version mismatch",
+		})
+	case errors.Is(err, service.ErrInvalidPatch):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid patch",
+			"details": err.Error(),
+		})
+	default:
+		requestLogger(c, h.logger).Error(message,
+			zap.String("product_id", productID),
+			zap.Error(err))
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": message,
+		})
+	}
+}
+
 func (h *ProductHandler) DeductStock(c *gin.Context) {
-	productID := c.Param("id")
+	productID, ok := bindProductID(c)
+	if !ok {
+		return
+	}
 
 	var req domain.DeductStockRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		h.logger.Error("Invalid request", zap.Error(err))
+		requestLogger(c, h.logger).Error("Invalid request", zap.Error(err))
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error": "Invalid request format",
 		})
 		return
 	}
 
-	result, err := h.productService.DeductStock(c.Request.Context(), productID, req.Quantity)
+	origin := domain.StockOrigin{OrderID: req.OrderID, RequestID: c.GetString("request_id")}
+	result, err := h.productService.DeductStock(c.Request.Context(), productID, req.Quantity, origin)
 	if err != nil {
-		if err == service.ErrProductNotFound {
+		switch {
+		case errors.Is(err, service.ErrProductNotFound):
 			c.JSON(http.StatusNotFound, gin.H{
 				"error": "Product not found",
 			})
-			return
-		}
-
-		if err == service.ErrInsufficientStock {
+		case errors.Is(err, service.ErrInsufficientStock):
 			c.JSON(http.StatusBadRequest, gin.H{
 				"error":     "Insufficient stock",
 				"available": result.PreviousStock,
 				"requested": req.Quantity,
 			})
-			return
+		case errors.Is(err, service.ErrInvalidBatch):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request format",
+				"details": err.Error(),
+			})
+		case errors.Is(err, service.ErrVersionConflict):
+			c.JSON(http.StatusConflict, gin.H{
+				"error": "Concurrent stock update, please retry",
+			})
+		default:
+			requestLogger(c, h.logger).Error("Failed to deduct stock",
+				zap.String("product_id", productID),
+				zap.Error(err))
+
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to deduct stock",
+			})
 		}
+		return
+	}
 
-		h.logger.Error("Failed to deduct stock",
-			zap.String("product_id", productID),
-			zap.Error(err))
+	c.JSON(http.StatusOK, result)
+}
 
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to deduct stock",
+func newProductResponse(product *domain.Product) domain.ProductResponse {
+	return domain.ProductResponse{
+		ProductID: product.ProductID,
+		Name:      product.Name,
+		Stock:     product.Stock,
+		Price:     product.Price,
+		Reserved:  product.Reserved,
+		Version:   product.Version,
+	}
+}
+
+// DeductStockBatch - 여러 상품의 재고를 원자적으로 차감 (하나라도 실패하면 전체 미반영)
+func (h *ProductHandler) DeductStockBatch(c *gin.Context) {
+	var req domain.BatchDeductStockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		requestLogger(c, h.logger).Error("Invalid request", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, result)
+	origin := domain.StockOrigin{OrderID: req.OrderID, RequestID: c.GetString("request_id")}
+	results, err := h.productService.DeductStockBatch(c.Request.Context(), req.Items, origin)
+	if err != nil {
+		var itemErr *domain.StockItemError
+		switch {
+		case errors.As(err, &itemErr) && errors.Is(err, service.ErrProductNotFound):
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":      "Product not found",
+				"product_id": itemErr.ProductID,
+			})
+		case errors.As(err, &itemErr) && errors.Is(err, service.ErrInsufficientStock):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":      "Insufficient stock",
+				"product_id": itemErr.ProductID,
+				"available":  itemErr.Available,
+			})
+		case errors.Is(err, service.ErrInvalidBatch):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request format",
+				"details": err.Error(),
+			})
+		case errors.Is(err, service.ErrVersionConflict):
+			c.JSON(http.StatusConflict, gin.H{
+				"error": "Concurrent stock update, please retry",
+			})
+		default:
+			requestLogger(c, h.logger).Error("Failed to deduct stock batch",
+				zap.Int("items_count", len(req.Items)),
+				zap.Error(err))
+
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to deduct stock",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.BatchStockDeductionResponse{Items: results})
 }
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/cloud-wave-best-zizon/product-service/internal/domain"
+	"github.com/cloud-wave-best-zizon/product-service/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type ReservationHandler struct {
+	reservationService *service.ReservationService
+	logger             *zap.Logger
+}
+
+func NewReservationHandler(reservationService *service.ReservationService, logger *zap.Logger) *ReservationHandler {
+	return &ReservationHandler{
+		reservationService: reservationService,
+		logger:             logger,
+	}
+}
+
+func (h *ReservationHandler) CreateReservation(c *gin.Context) {
+	var req domain.CreateReservationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		requestLogger(c, h.logger).Error("Invalid request", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+		})
+		return
+	}
+
+	reservation, err := h.reservationService.Reserve(c.Request.Context(), req)
+	if err != nil {
+		var itemErr *domain.StockItemError
+		switch {
+		case errors.Is(err, service.ErrProductNotFound):
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Product not found",
+			})
+		case errors.Is(err, service.ErrInvalidProductID), errors.Is(err, service.ErrInvalidReservationTTL):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request format",
+				"details": err.Error(),
+			})
+		case errors.As(err, &itemErr) && errors.Is(err, service.ErrInsufficientStock):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":     "Insufficient stock",
+				"available": itemErr.Available,
+				"requested": req.Quantity,
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to create reservation",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, reservation)
+}
+
+func (h *ReservationHandler) GetReservation(c *gin.Context) {
+	reservation, err := h.reservationService.GetReservation(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "Failed to get reservation")
+		return
+	}
+
+	c.JSON(http.StatusOK, reservation)
+}
+
+func (h *ReservationHandler) ConfirmReservation(c *gin.Context) {
+	reservation, err := h.reservationService.Confirm(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "Failed to confirm reservation")
+		return
+	}
+
+	c.JSON(http.StatusOK, reservation)
+}
+
+func (h *ReservationHandler) ReleaseReservation(c *gin.Context) {
+	reservation, err := h.reservationService.Release(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "Failed to release reservation")
+		return
+	}
+
+	c.JSON(http.StatusOK, reservation)
+}
+
+func (h *ReservationHandler) handleError(c *gin.Context, err error, message string) {
+	switch err {
+	case service.ErrReservationNotFound:
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Reservation not found",
+		})
+	case service.ErrReservationNotPending:
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "Reservation is already confirmed or released",
+		})
+	case service.ErrReservationExpired:
+		c.JSON(http.StatusGone, gin.H{
+			"error": "Reservation expired",
+		})
+	default:
+		requestLogger(c, h.logger).Error(message,
+			zap.String("reservation_id", c.Param("id")),
+			zap.Error(err))
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": message,
+		})
+	}
+}
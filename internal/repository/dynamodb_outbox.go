@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/cloud-wave-best-zizon/product-service/internal/domain"
+)
+
+// outboxPendingIndex - 미발행 메시지에만 있는 pending 속성(해시 키)과 created_at(정렬 키)의 sparse GSI
+// 발행 완료 시 pending 속성을 지우므로 인덱스에는 미발행 메시지만 남고, 발행된 메시지는 TTL로 정리된다.
+const (
+	outboxPendingIndex     = "pending-index"
+	outboxPendingAttribute = "pending"
+)
+
+// outboxCreatedAtLayout - 문자열 정렬 순서가 시간 순서와 같도록 UTC, 고정 자릿수로 기록하는 created_at 형식
+const outboxCreatedAtLayout = "2006-01-02T15:04:05.000000000Z07:00"
+
+var outboxPendingGSI = globalIndex{
+	name:      outboxPendingIndex,
+	hashKey:   outboxPendingAttribute,
+	hashType:  types.ScalarAttributeTypeS,
+	rangeKey:  "created_at",
+	rangeType: types.ScalarAttributeTypeS,
+}
+
+// ListPendingOutbox - sparse GSI에서 오래된 미발행 메시지부터 limit개만 조회 (GSI는 최종 일관성이라 방금 발행한 메시지가
+// 한 번 더 보일 수 있으나 at-least-once 발행이므로 허용한다)
+func (s *DynamoProductStore) ListPendingOutbox(ctx context.Context, limit int) ([]*domain.OutboxMessage, error) {
+	keyCond := expression.Key(outboxPendingAttribute).Equal(expression.Value(string(domain.OutboxPending)))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	// 정렬 키(created_at) 오름차순으로 한 페이지만 읽으므로 적체가 커져도 주기당 읽는 양은 limit개로 일정하다
+	result, err := s.client.Query(ctx, &dynamodb.QueryInput{
+		TableName:                 aws.String(s.tables.Outbox),
+		IndexName:                 aws.String(outboxPendingIndex),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ScanIndexForward:          aws.Bool(true),
+		Limit:                     aws.Int32(int32(limit)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pending outbox: %w", err)
+	}
+
+	pending := make([]*domain.OutboxMessage, 0, len(result.Items))
+	if err := attributevalue.UnmarshalListOfMaps(result.Items, &pending); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal outbox messages: %w", err)
+	}
+	return pending, nil
+}
+
+// MarkOutboxSent - 발행 완료 표시 후 pending 인덱스에서 빼고 expires_at(TTL 속성)을 지정해 보존 기간 이후 자동 삭제
+func (s *DynamoProductStore) MarkOutboxSent(ctx context.Context, messageID string, sentAt time.Time) error {
+	update := expression.Set(expression.Name("status"), expression.Value(domain.OutboxSent)).
+		Set(expression.Name("expires_at"), expression.Value(attributevalue.UnixTime(sentAt.Add(sentOutboxRetention)))).
+		Remove(expression.Name(outboxPendingAttribute))
+
+	return s.updateOutbox(ctx, messageID, update)
+}
+
+func (s *DynamoProductStore) MarkOutboxFailed(ctx context.Context, messageID string, attempts int, nextAttemptAt time.Time, lastErr string) error {
+	update := expression.Set(expression.Name("attempts"), expression.Value(attempts)).
+		Set(expression.Name("next_attempt_at"), expression.Value(attributevalue.UnixTime(nextAttemptAt))).
+		Set(expression.Name("last_error"), expression.Value(lastErr))
+
+	return s.updateOutbox(ctx, messageID, update)
+}
+
+func (s *DynamoProductStore) updateOutbox(ctx context.Context, messageID string, update expression.UpdateBuilder) error {
+	expr, err := expression.NewBuilder().
+		WithUpdate(update).
+		WithCondition(expression.AttributeExists(expression.Name("message_id"))).
+		Build()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(s.tables.Outbox),
+		Key:                       outboxKey(messageID),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		UpdateExpression:          expr.Update(),
+		ConditionExpression:       expr.Condition(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update outbox message: %w", err)
+	}
+	return nil
+}
+
+// outboxPuts - 아웃박스 메시지를 트랜잭션 Put 항목으로 변환
+func (s *DynamoProductStore) outboxPuts(messages []*domain.OutboxMessage) ([]types.TransactWriteItem, error) {
+	items := make([]types.TransactWriteItem, 0, len(messages))
+	for _, message := range messages {
+		av, err := attributevalue.MarshalMap(message)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal outbox message: %w", err)
+		}
+		av[outboxPendingAttribute] = &types.AttributeValueMemberS{Value: string(domain.OutboxPending)}
+		av["created_at"] = &types.AttributeValueMemberS{Value: message.CreatedAt.UTC().Format(outboxCreatedAtLayout)}
+		items = append(items, types.TransactWriteItem{
+			Put: &types.Put{
+				TableName:           aws.String(s.tables.Outbox),
+				Item:                av,
+				ConditionExpression: aws.String("attribute_not_exists(message_id)"),
+			},
+		})
+	}
+	return items, nil
+}
+
+func outboxKey(messageID string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"message_id": &types.AttributeValueMemberS{Value: messageID},
+	}
+}
@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/cloud-wave-best-zizon/product-service/internal/domain"
+)
+
+// processedRecord - 처리 완료 테이블 항목 (expires_at은 DynamoDB TTL 속성)
+type processedRecord struct {
+	DedupeKey   string                `dynamodbav:"dedupe_key"`
+	EventID     string                `dynamodbav:"event_id"`
+	OrderID     int                   `dynamodbav:"order_id"`
+	Action      string                `dynamodbav:"action,omitempty"`
+	Items       []processedRecordItem `dynamodbav:"items,omitempty"`
+	ProcessedAt time.Time             `dynamodbav:"processed_at"`
+	ExpiresAt   time.Time             `dynamodbav:"expires_at,unixtime"`
+}
+
+type processedRecordItem struct {
+	ProductID string `dynamodbav:"product_id"`
+	Quantity  int    `dynamodbav:"quantity"`
+}
+
+// GetProcessedOrder - order#<id> 기록 조회 (TTL 삭제 지연을 고려해 expires_at 직접 비교)
+func (s *DynamoProductStore) GetProcessedOrder(ctx context.Context, orderID int) (*domain.ProcessedEvent, error) {
+	result, err := s.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(s.tables.Processed),
+		Key:            processedKey(orderProcessedKey(domain.ProcessedActionDeduct, orderID)),
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get processed order: %w", err)
+	}
+	if result.Item == nil {
+		return nil, ErrProcessedEventNotFound
+	}
+
+	var record processedRecord
+	if err := attributevalue.UnmarshalMap(result.Item, &record); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal processed order: %w", err)
+	}
+	if !time.Now().Before(record.ExpiresAt) {
+		return nil, ErrProcessedEventNotFound
+	}
+
+	processed := &domain.ProcessedEvent{
+		EventID:     record.EventID,
+		OrderID:     record.OrderID,
+		Action:      record.Action,
+		Items:       make([]domain.StockDeductionItem, 0, len(record.Items)),
+		ProcessedAt: record.ProcessedAt,
+		ExpiresAt:   record.ExpiresAt,
+	}
+	for _, item := range record.Items {
+		processed.Items = append(processed.Items, domain.StockDeductionItem{ProductID: item.ProductID, Quantity: item.Quantity})
+	}
+	return processed, nil
+}
+
+// RecordOutcome - 처리 완료 기록과 아웃박스 메시지를 한 트랜잭션으로 기록
+func (s *DynamoProductStore) RecordOutcome(ctx context.Context, processed *domain.ProcessedEvent, messages []*domain.OutboxMessage) error {
+	transactItems, err := s.outboxPuts(messages)
+	if err != nil {
+		return err
+	}
+	if processed != nil {
+		processedItems, err := s.processedPuts(processed)
+		if err != nil {
+			return err
+		}
+		transactItems = append(transactItems, processedItems...)
+	}
+	if len(transactItems) == 0 {
+		return nil
+	}
+
+	_, err = s.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: transactItems,
+	})
+	if err != nil {
+		var tce *types.TransactionCanceledException
+		if errors.As(err, &tce) {
+			// 아웃박스 Put은 새 메시지 ID이므로 조건 실패는 처리 완료 기록 중복
+			for _, reason := range tce.CancellationReasons {
+				if aws.ToString(reason.Code) == "ConditionalCheckFailed" {
+					return ErrDuplicateEvent
+				}
+			}
+		}
+		return fmt.Errorf("failed to record event outcome: %w", err)
+	}
+	return nil
+}
+
+// isProcessed - 만료되지 않은 처리 완료 기록이 있는지 확인
+// TTL 삭제는 지연될 수 있으므로 expires_at을 직접 비교한다.
+func (s *DynamoProductStore) isProcessed(ctx context.Context, processed *domain.ProcessedEvent) (bool, error) {
+	now := time.Now()
+	for _, key := range processedKeys(processed) {
+		result, err := s.client.GetItem(ctx, &dynamodb.GetItemInput{
+			TableName:      aws.String(s.tables.Processed),
+			Key:            processedKey(key),
+			ConsistentRead: aws.Bool(true),
+		})
+		if err != nil {
+			return false, fmt.Errorf("failed to get processed event: %w", err)
+		}
+		if result.Item == nil {
+			continue
+		}
+
+		var record processedRecord
+		if err := attributevalue.UnmarshalMap(result.Item, &record); err != nil {
+			return false, fmt.Errorf("failed to unmarshal processed event: %w", err)
+		}
+		if now.Before(record.ExpiresAt) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// processedPuts - 처리 완료 기록을 조건부 Put 항목으로 변환 (만료되지 않은 기록이 있으면 트랜잭션 취소)
+func (s *DynamoProductStore) processedPuts(processed *domain.ProcessedEvent) ([]types.TransactWriteItem, error) {
+	condition := expression.AttributeNotExists(expression.Name("dedupe_key")).
+		Or(expression.Name("expires_at").LessThanEqual(expression.Value(attributevalue.UnixTime(processed.ProcessedAt))))
+	expr, err := expression.NewBuilder().WithCondition(condition).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	recordItems := make([]processedRecordItem, 0, len(processed.Items))
+	for _, item := range processed.Items {
+		recordItems = append(recordItems, processedRecordItem{ProductID: item.ProductID, Quantity: item.Quantity})
+	}
+
+	keys := processedKeys(processed)
+	items := make([]types.TransactWriteItem, 0, len(keys))
+	for _, key := range keys {
+		av, err := attributevalue.MarshalMap(processedRecord{
+			DedupeKey:   key,
+			EventID:     processed.EventID,
+			OrderID:     processed.OrderID,
+			Action:      processed.Action,
+			Items:       recordItems,
+			ProcessedAt: processed.ProcessedAt,
+			ExpiresAt:   processed.ExpiresAt,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal processed event: %w", err)
+		}
+
+		items = append(items, types.TransactWriteItem{
+			Put: &types.Put{
+				TableName:                 aws.String(s.tables.Processed),
+				Item:                      av,
+				ExpressionAttributeNames:  expr.Names(),
+				ExpressionAttributeValues: expr.Values(),
+				ConditionExpression:       expr.Condition(),
+			},
+		})
+	}
+	return items, nil
+}
+
+// processedKeysOf - 트랜잭션에 포함된 처리 완료 기록 키 (nil이면 없음)
+func processedKeysOf(processed *domain.ProcessedEvent) []string {
+	if processed == nil {
+		return nil
+	}
+	return processedKeys(processed)
+}
+
+func processedKey(key string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"dedupe_key": &types.AttributeValueMemberS{Value: key},
+	}
+}
@@ -0,0 +1,311 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/cloud-wave-best-zizon/product-service/internal/domain"
+)
+
+// reservationExpiryIndex - status(해시) + expires_at(정렬) GSI
+// 만료 스윕은 pending 파티션에서 만료 시각이 지난 예약만 조회한다 (테이블 전체 스캔 없음).
+const reservationExpiryIndex = "status-expires_at-index"
+
+var reservationExpiryGSI = globalIndex{
+	name:      reservationExpiryIndex,
+	hashKey:   "status",
+	hashType:  types.ScalarAttributeTypeS,
+	rangeKey:  "expires_at",
+	rangeType: types.ScalarAttributeTypeN,
+}
+
+func (s *DynamoProductStore) CreateReservation(ctx context.Context, reservation *domain.Reservation) error {
+	// 재고가 충분할 때만 stock -> reserved 이동
+	productUpdate := expression.Set(
+		expression.Name("stock"),
+		expression.Minus(expression.Name("stock"), expression.Value(reservation.Quantity)),
+	).Set(
+		expression.Name("updated_at"), expression.Value(reservation.CreatedAt),
+	).Add(
+		expression.Name("reserved"), expression.Value(reservation.Quantity),
+	).Add(
+		expression.Name("version"), expression.Value(1),
+	)
+	productCondition := expression.AttributeExists(expression.Name("product_id")).
+		And(expression.GreaterThanEqual(expression.Name("stock"), expression.Value(reservation.Quantity)))
+
+	productExpr, err := expression.NewBuilder().
+		WithUpdate(productUpdate).
+		WithCondition(productCondition).
+		Build()
+	if err != nil {
+		return err
+	}
+
+	av, err := attributevalue.MarshalMap(reservation)
+	if err != nil {
+		return fmt.Errorf("failed to marshal reservation: %w", err)
+	}
+
+	_, err = s.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{
+				Update: &types.Update{
+					TableName:                           aws.String(s.tables.Products),
+					Key:                                 productKey(reservation.ProductID),
+					ExpressionAttributeNames:            productExpr.Names(),
+					ExpressionAttributeValues:           productExpr.Values(),
+					UpdateExpression:                    productExpr.Update(),
+					ConditionExpression:                 productExpr.Condition(),
+					ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+				},
+			},
+			{
+				Put: &types.Put{
+					TableName:           aws.String(s.tables.Reservations),
+					Item:                av,
+					ConditionExpression: aws.String("attribute_not_exists(reservation_id)"),
+				},
+			},
+		},
+	})
+	if err != nil {
+		var tce *types.TransactionCanceledException
+		if errors.As(err, &tce) && len(tce.CancellationReasons) > 0 &&
+			aws.ToString(tce.CancellationReasons[0].Code) == "ConditionalCheckFailed" {
+			reason := tce.CancellationReasons[0]
+			if len(reason.Item) == 0 {
+				return &domain.StockItemError{ProductID: reservation.ProductID, Err: ErrProductNotFound}
+			}
+			var current domain.Product
+			_ = attributevalue.UnmarshalMap(reason.Item, &current)
+			return &domain.StockItemError{ProductID: reservation.ProductID, Available: current.Stock, Err: ErrInsufficientStock}
+		}
+		return fmt.Errorf("failed to create reservation: %w", err)
+	}
+
+	return nil
+}
+
+func (s *DynamoProductStore) GetReservation(ctx context.Context, reservationID string) (*domain.Reservation, error) {
+	result, err := s.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(s.tables.Reservations),
+		Key:       reservationKey(reservationID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reservation: %w", err)
+	}
+
+	if result.Item == nil {
+		return nil, ErrReservationNotFound
+	}
+
+	var reservation domain.Reservation
+	if err := attributevalue.UnmarshalMap(result.Item, &reservation); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal reservation: %w", err)
+	}
+
+	return &reservation, nil
+}
+
+func (s *DynamoProductStore) ConfirmReservation(ctx context.Context, reservationID string, now time.Time) (*domain.Reservation, error) {
+	reservation, err := s.GetReservation(ctx, reservationID)
+	if err != nil {
+		return nil, err
+	}
+	if reservation.Status != domain.ReservationPending {
+		return nil, ErrReservationNotPending
+	}
+	if !now.Before(reservation.ExpiresAt) {
+		return nil, ErrReservationExpired
+	}
+
+	// 확정 시 reserved 수량만 소진 (stock은 예약 시점에 이미 차감됨)
+	productUpdate := expression.Set(expression.Name("updated_at"), expression.Value(now)).
+		Add(expression.Name("reserved"), expression.Value(-reservation.Quantity)).
+		Add(expression.Name("version"), expression.Value(1))
+
+	return s.completeReservation(ctx, reservation, domain.ReservationConfirmed, now, productUpdate,
+		expression.Name("expires_at").GreaterThan(expression.Value(attributevalue.UnixTime(now))))
+}
+
+func (s *DynamoProductStore) ReleaseReservation(ctx context.Context, reservationID string, status domain.ReservationStatus) (*domain.Reservation, error) {
+	reservation, err := s.GetReservation(ctx, reservationID)
+	if err != nil {
+		return nil, err
+	}
+	if reservation.Status != domain.ReservationPending {
+		return nil, ErrReservationNotPending
+	}
+
+	now := time.Now()
+	// 해제/만료 시 reserved 수량을 stock으로 복구
+	productUpdate := expression.Set(
+		expression.Name("stock"),
+		expression.Plus(expression.Name("stock"), expression.Value(reservation.Quantity)),
+	).Set(
+		expression.Name("updated_at"), expression.Value(now),
+	).Add(
+		expression.Name("reserved"), expression.Value(-reservation.Quantity),
+	).Add(
+		expression.Name("version"), expression.Value(1),
+	)
+
+	return s.completeReservation(ctx, reservation, status, now, productUpdate)
+}
+
+// ListExpiredReservations - GSI에서 오래 만료된 순으로 조회 (GSI는 최종 일관성이라 이미 처리된 예약이 섞일 수 있으나
+// 상태 전환 조건(status = pending)에서 걸러진다)
+func (s *DynamoProductStore) ListExpiredReservations(ctx context.Context, now time.Time, limit int) ([]*domain.Reservation, error) {
+	keyCond := expression.Key("status").Equal(expression.Value(domain.ReservationPending)).
+		And(expression.Key("expires_at").LessThanEqual(expression.Value(attributevalue.UnixTime(now))))
+
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	input := &dynamodb.QueryInput{
+		TableName:                 aws.String(s.tables.Reservations),
+		IndexName:                 aws.String(reservationExpiryIndex),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+		Limit:                     aws.Int32(int32(limit)),
+	}
+
+	expired := make([]*domain.Reservation, 0)
+	paginator := dynamodb.NewQueryPaginator(s.client, input)
+	for paginator.HasMorePages() && len(expired) < limit {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query expired reservations: %w", err)
+		}
+
+		var reservations []*domain.Reservation
+		if err := attributevalue.UnmarshalListOfMaps(page.Items, &reservations); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal reservations: %w", err)
+		}
+		expired = append(expired, reservations...)
+	}
+
+	if len(expired) > limit {
+		expired = expired[:limit]
+	}
+	return expired, nil
+}
+
+// completeReservation - pending 예약의 상태 전환과 상품 재고 갱신을 하나의 트랜잭션으로 반영
+func (s *DynamoProductStore) completeReservation(ctx context.Context, reservation *domain.Reservation, status domain.ReservationStatus, now time.Time, productUpdate expression.UpdateBuilder, extra ...expression.ConditionBuilder) (*domain.Reservation, error) {
+	reservationUpdate := expression.Set(expression.Name("status"), expression.Value(status)).
+		Set(expression.Name("updated_at"), expression.Value(now))
+	reservationCondition := expression.Name("status").Equal(expression.Value(domain.ReservationPending))
+	if len(extra) > 0 {
+		reservationCondition = reservationCondition.And(extra[0], extra[1:]...)
+	}
+
+	reservationExpr, err := expression.NewBuilder().
+		WithUpdate(reservationUpdate).
+		WithCondition(reservationCondition).
+		Build()
+	if err != nil {
+		return nil, err
+	}
+	productExpr, err := expression.NewBuilder().
+		WithUpdate(productUpdate).
+		WithCondition(expression.AttributeExists(expression.Name("product_id"))).
+		Build()
+	if err != nil {
+		return nil, err
+	}
+
+	reservationItem := types.TransactWriteItem{
+		Update: &types.Update{
+			TableName:                           aws.String(s.tables.Reservations),
+			Key:                                 reservationKey(reservation.ReservationID),
+			ExpressionAttributeNames:            reservationExpr.Names(),
+			ExpressionAttributeValues:           reservationExpr.Values(),
+			UpdateExpression:                    reservationExpr.Update(),
+			ConditionExpression:                 reservationExpr.Condition(),
+			ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+		},
+	}
+
+	_, err = s.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			reservationItem,
+			{
+				Update: &types.Update{
+					TableName:                 aws.String(s.tables.Products),
+					Key:                       productKey(reservation.ProductID),
+					ExpressionAttributeNames:  productExpr.Names(),
+					ExpressionAttributeValues: productExpr.Values(),
+					UpdateExpression:          productExpr.Update(),
+					ConditionExpression:       productExpr.Condition(),
+				},
+			},
+		},
+	})
+	if err != nil {
+		var tce *types.TransactionCanceledException
+		if !errors.As(err, &tce) || len(tce.CancellationReasons) < 2 {
+			return nil, fmt.Errorf("failed to update reservation: %w", err)
+		}
+
+		if aws.ToString(tce.CancellationReasons[0].Code) == "ConditionalCheckFailed" {
+			return nil, reservationConditionFailure(tce.CancellationReasons[0].Item)
+		}
+		if aws.ToString(tce.CancellationReasons[1].Code) != "ConditionalCheckFailed" {
+			return nil, fmt.Errorf("failed to update reservation: %w", err)
+		}
+
+		// 상품이 삭제된 경우 예약 상태만 변경
+		_, err = s.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+			TableName:                           reservationItem.Update.TableName,
+			Key:                                 reservationItem.Update.Key,
+			ExpressionAttributeNames:            reservationItem.Update.ExpressionAttributeNames,
+			ExpressionAttributeValues:           reservationItem.Update.ExpressionAttributeValues,
+			UpdateExpression:                    reservationItem.Update.UpdateExpression,
+			ConditionExpression:                 reservationItem.Update.ConditionExpression,
+			ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+		})
+		if err != nil {
+			var ccf *types.ConditionalCheckFailedException
+			if errors.As(err, &ccf) {
+				return nil, reservationConditionFailure(ccf.Item)
+			}
+			return nil, fmt.Errorf("failed to update reservation: %w", err)
+		}
+	}
+
+	updated := *reservation
+	updated.Status = status
+	updated.UpdatedAt = now
+	return &updated, nil
+}
+
+// reservationConditionFailure - 조건 실패 시 반환된 기존 예약으로 실패 원인 판별
+func reservationConditionFailure(item map[string]types.AttributeValue) error {
+	if len(item) == 0 {
+		return ErrReservationNotFound
+	}
+
+	var current domain.Reservation
+	if err := attributevalue.UnmarshalMap(item, &current); err == nil && current.Status == domain.ReservationPending {
+		return ErrReservationExpired
+	}
+	return ErrReservationNotPending
+}
+
+func reservationKey(reservationID string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"reservation_id": &types.AttributeValueMemberS{Value: reservationID},
+	}
+}
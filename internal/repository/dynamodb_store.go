@@ -0,0 +1,616 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/cloud-wave-best-zizon/product-service/internal/domain"
+	"github.com/cloud-wave-best-zizon/product-service/pkg/logging"
+	"go.uber.org/zap"
+)
+
+// 동시 수정으로 트랜잭션이 취소됐을 때 재시도 횟수와 재시도 간격 (지수 백오프 + full jitter)
+const (
+	maxTransactionRetries = 3
+	transactionRetryBase  = 20 * time.Millisecond
+	transactionRetryMax   = 500 * time.Millisecond
+)
+
+var errTransactionConflict = errors.New("transaction conflict")
+
+// TableNames - DynamoDB 백엔드가 사용하는 테이블 이름
+type TableNames struct {
+	Products     string
+	Reservations string
+	Outbox       string
+	Processed    string
+}
+
+var _ Store = (*DynamoProductStore)(nil)
+
+// DynamoProductStore - DynamoDB 기반 상품 저장소
+type DynamoProductStore struct {
+	client *dynamodb.Client
+	tables TableNames
+	logger *zap.Logger
+}
+
+func NewDynamoProductStore(client *dynamodb.Client, tables TableNames, logger *zap.Logger) *DynamoProductStore {
+	return &DynamoProductStore{
+		client: client,
+		tables: tables,
+		logger: logger,
+	}
+}
+
+// log - ctx의 요청 ID(request_id)를 붙인 로거
+func (s *DynamoProductStore) log(ctx context.Context) *zap.Logger {
+	return logging.FromContext(ctx, s.logger)
+}
+
+// CreateTableIfNotExists - DynamoDB Local 사용 시 테이블 생성
+func (s *DynamoProductStore) CreateTableIfNotExists(ctx context.Context) error {
+	if err := s.createTable(ctx, s.tables.Products, "product_id"); err != nil {
+		return err
+	}
+	if err := s.createTable(ctx, s.tables.Reservations, "reservation_id", reservationExpiryGSI); err != nil {
+		return err
+	}
+	if err := s.createTable(ctx, s.tables.Outbox, "message_id", outboxPendingGSI); err != nil {
+		return err
+	}
+	return s.createTable(ctx, s.tables.Processed, "dedupe_key")
+}
+
+// globalIndex - 테이블 생성 시 함께 만드는 GSI (해시 키 + 선택적 정렬 키, 모든 속성 프로젝션)
+type globalIndex struct {
+	name      string
+	hashKey   string
+	hashType  types.ScalarAttributeType
+	rangeKey  string
+	rangeType types.ScalarAttributeType
+}
+
+func (s *DynamoProductStore) createTable(ctx context.Context, tableName, hashKey string, indexes ...globalIndex) error {
+	// 테이블 존재 확인
+	_, err := s.client.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(tableName),
+	})
+
+	if err == nil {
+		// 테이블이 이미 존재함
+		return nil
+	}
+
+	// 테이블 생성
+	attributes := []types.AttributeDefinition{
+		{
+			AttributeName: aws.String(hashKey),
+			AttributeType: types.ScalarAttributeTypeS,
+		},
+	}
+	var gsis []types.GlobalSecondaryIndex
+	for _, index := range indexes {
+		attributes = append(attributes,
+			types.AttributeDefinition{AttributeName: aws.String(index.hashKey), AttributeType: index.hashType})
+		keySchema := []types.KeySchemaElement{
+			{AttributeName: aws.String(index.hashKey), KeyType: types.KeyTypeHash},
+		}
+		if index.rangeKey != "" {
+			attributes = append(attributes,
+				types.AttributeDefinition{AttributeName: aws.String(index.rangeKey), AttributeType: index.rangeType})
+			keySchema = append(keySchema,
+				types.KeySchemaElement{AttributeName: aws.String(index.rangeKey), KeyType: types.KeyTypeRange})
+		}
+		gsis = append(gsis, types.GlobalSecondaryIndex{
+			IndexName:  aws.String(index.name),
+			KeySchema:  keySchema,
+			Projection: &types.Projection{ProjectionType: types.ProjectionTypeAll},
+		})
+	}
+
+	_, err = s.client.CreateTable(ctx, &dynamodb.CreateTableInput{
+		TableName:              aws.String(tableName),
+		KeySchema:              []types.KeySchemaElement{{AttributeName: aws.String(hashKey), KeyType: types.KeyTypeHash}},
+		AttributeDefinitions:   attributes,
+		GlobalSecondaryIndexes: gsis,
+		BillingMode:            types.BillingModePayPerRequest,
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to create table %s: %w", tableName, err)
+	}
+
+	// 테이블이 활성화될 때까지 대기
+	waiter := dynamodb.NewTableExistsWaiter(s.client)
+	return waiter.Wait(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(tableName),
+	}, 30*time.Second)
+}
+
+// Ping - 사용하는 테이블을 모두 조회할 수 있고 쓰기 가능한 상태인지 확인 (readiness 용)
+func (s *DynamoProductStore) Ping(ctx context.Context) error {
+	for _, tableName := range []string{s.tables.Products, s.tables.Reservations, s.tables.Outbox, s.tables.Processed} {
+		out, err := s.client.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+			TableName: aws.String(tableName),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to describe table %s: %w", tableName, err)
+		}
+		if status := out.Table.TableStatus; status != types.TableStatusActive && status != types.TableStatusUpdating {
+			return fmt.Errorf("table %s is %s", tableName, status)
+		}
+	}
+	return nil
+}
+
+func (s *DynamoProductStore) CreateProduct(ctx context.Context, product *domain.Product) error {
+	av, err := attributevalue.MarshalMap(product)
+	if err != nil {
+		return fmt.Errorf("failed to marshal product: %w", err)
+	}
+
+	_, err = s.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(s.tables.Products),
+		Item:                av,
+		ConditionExpression: aws.String("attribute_not_exists(product_id)"),
+	})
+
+	if err != nil {
+		var ccf *types.ConditionalCheckFailedException
+		if errors.As(err, &ccf) {
+			return ErrProductAlreadyExists
+		}
+		return fmt.Errorf("failed to put item: %w", err)
+	}
+
+	return nil
+}
+
+func (s *DynamoProductStore) GetProduct(ctx context.Context, productID string) (*domain.Product, error) {
+	return s.getProduct(ctx, productID, false)
+}
+
+// getProduct - consistent가 true면 강한 일관성 읽기 (version 조건을 거는 트랜잭션 직전 읽기용)
+func (s *DynamoProductStore) getProduct(ctx context.Context, productID string, consistent bool) (*domain.Product, error) {
+	result, err := s.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(s.tables.Products),
+		Key:            productKey(productID),
+		ConsistentRead: aws.Bool(consistent),
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get item: %w", err)
+	}
+
+	if result.Item == nil {
+		return nil, ErrProductNotFound
+	}
+
+	var product domain.Product
+	if err := attributevalue.UnmarshalMap(result.Item, &product); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal product: %w", err)
+	}
+
+	return &product, nil
+}
+
+func (s *DynamoProductStore) UpdateProduct(ctx context.Context, product *domain.Product, expectedVersion int64) (*domain.Product, error) {
+	update := expression.Set(expression.Name("name"), expression.Value(product.Name)).
+		Set(expression.Name("price"), expression.Value(product.Price)).
+		Set(expression.Name("stock"), expression.Value(product.Stock)).
+		Set(expression.Name("updated_at"), expression.Value(product.UpdatedAt)).
+		Add(expression.Name("version"), expression.Value(1))
+
+	expr, err := expression.NewBuilder().
+		WithUpdate(update).
+		WithCondition(versionCondition(expectedVersion)).
+		Build()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(s.tables.Products),
+		Key:                       productKey(product.ProductID),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		UpdateExpression:          expr.Update(),
+		ConditionExpression:       expr.Condition(),
+		ReturnValues:              types.ReturnValueAllNew,
+		// 조건 실패 시 미존재와 버전 충돌을 구분하기 위해 기존 항목 반환
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+	})
+	if err != nil {
+		var ccf *types.ConditionalCheckFailedException
+		if errors.As(err, &ccf) {
+			return nil, conditionFailure(ccf)
+		}
+		return nil, fmt.Errorf("failed to update item: %w", err)
+	}
+
+	var updated domain.Product
+	if err := attributevalue.UnmarshalMap(result.Attributes, &updated); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal product: %w", err)
+	}
+
+	return &updated, nil
+}
+
+func (s *DynamoProductStore) DeleteProduct(ctx context.Context, productID string, expectedVersion int64) error {
+	expr, err := expression.NewBuilder().
+		WithCondition(versionCondition(expectedVersion)).
+		Build()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName:                           aws.String(s.tables.Products),
+		Key:                                 productKey(productID),
+		ExpressionAttributeNames:            expr.Names(),
+		ExpressionAttributeValues:           expr.Values(),
+		ConditionExpression:                 expr.Condition(),
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+	})
+	if err != nil {
+		var ccf *types.ConditionalCheckFailedException
+		if errors.As(err, &ccf) {
+			return conditionFailure(ccf)
+		}
+		return fmt.Errorf("failed to delete item: %w", err)
+	}
+
+	return nil
+}
+
+func (s *DynamoProductStore) ListProducts(ctx context.Context, limit int, cursor string) ([]*domain.Product, string, error) {
+	limit = listLimit(limit)
+	after, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	input := &dynamodb.ScanInput{
+		TableName: aws.String(s.tables.Products),
+		Limit:     aws.Int32(int32(limit)),
+	}
+	if after != "" {
+		input.ExclusiveStartKey = productKey(after)
+	}
+
+	result, err := s.client.Scan(ctx, input)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to scan items: %w", err)
+	}
+
+	products := make([]*domain.Product, 0, len(result.Items))
+	if err := attributevalue.UnmarshalListOfMaps(result.Items, &products); err != nil {
+		return nil, "", fmt.Errorf("failed to unmarshal products: %w", err)
+	}
+
+	nextCursor := ""
+	if key, ok := result.LastEvaluatedKey["product_id"].(*types.AttributeValueMemberS); ok {
+		nextCursor = encodeCursor(key.Value)
+	}
+
+	return products, nextCursor, nil
+}
+
+// sleepTransactionBackoff - 트랜잭션 재시도 전 대기 (0 ~ min(base*2^attempt, max) 사이 임의 시간, ctx 취소 시 오류)
+// 동시에 충돌한 요청들이 같은 간격으로 다시 부딪히지 않도록 대기 시간을 흩뜨린다.
+func sleepTransactionBackoff(ctx context.Context, attempt int) error {
+	backoff := transactionRetryBase << attempt
+	if backoff > transactionRetryMax || backoff <= 0 {
+		backoff = transactionRetryMax
+	}
+
+	timer := time.NewTimer(time.Duration(rand.Int63n(int64(backoff)) + 1))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// DeductStockBatch - TransactWriteItems로 모든 상품 차감과 아웃박스 메시지를 원자적으로 기록
+// 읽은 시점의 version을 조건으로 걸어 이전/이후 재고를 정확히 계산하고, 동시 수정 시 재시도한다.
+func (s *DynamoProductStore) DeductStockBatch(ctx context.Context, deduction *StockDeduction) ([]domain.StockDeductionResponse, error) {
+	// 재고 부족 등 다른 실패보다 중복 여부를 먼저 판단 (경합은 트랜잭션 조건으로 처리)
+	if deduction.Processed != nil {
+		processed, err := s.isProcessed(ctx, deduction.Processed)
+		if err != nil {
+			return nil, err
+		}
+		if processed {
+			return nil, ErrDuplicateEvent
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		results, err := s.deductStockBatchOnce(ctx, deduction)
+		if errors.Is(err, errTransactionConflict) {
+			if attempt < maxTransactionRetries {
+				s.log(ctx).Warn("Stock deduction conflicted with a concurrent update, retrying",
+					zap.Int("attempt", attempt+1),
+					zap.Int("items", len(deduction.Items)))
+				if err := sleepTransactionBackoff(ctx, attempt); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			s.log(ctx).Error("Stock deduction conflicted too many times",
+				zap.Int("attempts", attempt+1),
+				zap.Int("items", len(deduction.Items)))
+			return nil, ErrVersionConflict
+		}
+		return results, err
+	}
+}
+
+func (s *DynamoProductStore) deductStockBatchOnce(ctx context.Context, deduction *StockDeduction) ([]domain.StockDeductionResponse, error) {
+	items := deduction.Items
+	now := time.Now()
+	transactItems := make([]types.TransactWriteItem, 0, len(items))
+	results := make([]domain.StockDeductionResponse, 0, len(items))
+
+	for _, item := range items {
+		product, err := s.getProduct(ctx, item.ProductID, true)
+		if err != nil {
+			if errors.Is(err, ErrProductNotFound) {
+				return nil, &domain.StockItemError{ProductID: item.ProductID, Err: ErrProductNotFound}
+			}
+			return nil, err
+		}
+		if product.Stock < item.Quantity {
+			return nil, &domain.StockItemError{ProductID: item.ProductID, Available: product.Stock, Err: ErrInsufficientStock}
+		}
+
+		newStock := product.Stock - item.Quantity
+		update := expression.Set(expression.Name("stock"), expression.Value(newStock)).
+			Set(expression.Name("updated_at"), expression.Value(now)).
+			Add(expression.Name("version"), expression.Value(1))
+
+		expr, err := expression.NewBuilder().
+			WithUpdate(update).
+			WithCondition(versionCondition(product.Version)).
+			Build()
+		if err != nil {
+			return nil, err
+		}
+
+		transactItems = append(transactItems, types.TransactWriteItem{
+			Update: &types.Update{
+				TableName:                           aws.String(s.tables.Products),
+				Key:                                 productKey(item.ProductID),
+				ExpressionAttributeNames:            expr.Names(),
+				ExpressionAttributeValues:           expr.Values(),
+				UpdateExpression:                    expr.Update(),
+				ConditionExpression:                 expr.Condition(),
+				ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+			},
+		})
+		results = append(results, domain.StockDeductionResponse{
+			ProductID:     item.ProductID,
+			PreviousStock: product.Stock,
+			NewStock:      newStock,
+			Deducted:      item.Quantity,
+		})
+	}
+
+	messages, err := deduction.outboxMessages(results)
+	if err != nil {
+		return nil, err
+	}
+	outboxItems, err := s.outboxPuts(messages)
+	if err != nil {
+		return nil, err
+	}
+	transactItems = append(transactItems, outboxItems...)
+	if deduction.Processed != nil {
+		processedItems, err := s.processedPuts(deduction.Processed)
+		if err != nil {
+			return nil, err
+		}
+		transactItems = append(transactItems, processedItems...)
+	}
+
+	_, err = s.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: transactItems,
+	})
+	if err != nil {
+		var tce *types.TransactionCanceledException
+		if errors.As(err, &tce) {
+			return nil, deductionCancellationError(tce, items, len(transactItems)-len(processedKeysOf(deduction.Processed)))
+		}
+		return nil, fmt.Errorf("failed to transact write items: %w", err)
+	}
+
+	return results, nil
+}
+
+// RestockBatch - 상품별 version CAS로 수량을 되돌리고 아웃박스/처리 완료 기록을 한 트랜잭션으로 반영
+// 삭제된 상품은 건너뛰며, 동시 수정 시 재시도한다.
+func (s *DynamoProductStore) RestockBatch(ctx context.Context, restock *StockRestock) ([]domain.StockRestockResponse, error) {
+	if restock.Processed != nil {
+		processed, err := s.isProcessed(ctx, restock.Processed)
+		if err != nil {
+			return nil, err
+		}
+		if processed {
+			return nil, ErrDuplicateEvent
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		results, err := s.restockBatchOnce(ctx, restock)
+		if errors.Is(err, errTransactionConflict) {
+			if attempt < maxTransactionRetries {
+				s.log(ctx).Warn("Restock conflicted with a concurrent update, retrying",
+					zap.Int("attempt", attempt+1),
+					zap.Int("items", len(restock.Items)))
+				if err := sleepTransactionBackoff(ctx, attempt); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			s.log(ctx).Error("Restock conflicted too many times",
+				zap.Int("attempts", attempt+1),
+				zap.Int("items", len(restock.Items)))
+			return nil, ErrVersionConflict
+		}
+		return results, err
+	}
+}
+
+func (s *DynamoProductStore) restockBatchOnce(ctx context.Context, restock *StockRestock) ([]domain.StockRestockResponse, error) {
+	now := time.Now()
+	transactItems := make([]types.TransactWriteItem, 0, len(restock.Items))
+	results := make([]domain.StockRestockResponse, 0, len(restock.Items))
+
+	for _, item := range restock.Items {
+		product, err := s.getProduct(ctx, item.ProductID, true)
+		if err != nil {
+			if errors.Is(err, ErrProductNotFound) {
+				continue
+			}
+			return nil, err
+		}
+
+		newStock := product.Stock + item.Quantity
+		update := expression.Set(expression.Name("stock"), expression.Value(newStock)).
+			Set(expression.Name("updated_at"), expression.Value(now)).
+			Add(expression.Name("version"), expression.Value(1))
+
+		expr, err := expression.NewBuilder().
+			WithUpdate(update).
+			WithCondition(versionCondition(product.Version)).
+			Build()
+		if err != nil {
+			return nil, err
+		}
+
+		transactItems = append(transactItems, types.TransactWriteItem{
+			Update: &types.Update{
+				TableName:                 aws.String(s.tables.Products),
+				Key:                       productKey(item.ProductID),
+				ExpressionAttributeNames:  expr.Names(),
+				ExpressionAttributeValues: expr.Values(),
+				UpdateExpression:          expr.Update(),
+				ConditionExpression:       expr.Condition(),
+			},
+		})
+		results = append(results, domain.StockRestockResponse{
+			ProductID:     item.ProductID,
+			PreviousStock: product.Stock,
+			NewStock:      newStock,
+			Restocked:     item.Quantity,
+		})
+	}
+
+	messages, err := restock.outboxMessages(results)
+	if err != nil {
+		return nil, err
+	}
+	outboxItems, err := s.outboxPuts(messages)
+	if err != nil {
+		return nil, err
+	}
+	transactItems = append(transactItems, outboxItems...)
+	processedStart := len(transactItems)
+	if restock.Processed != nil {
+		processedItems, err := s.processedPuts(restock.Processed)
+		if err != nil {
+			return nil, err
+		}
+		transactItems = append(transactItems, processedItems...)
+	}
+	if len(transactItems) == 0 {
+		return results, nil
+	}
+
+	_, err = s.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: transactItems,
+	})
+	if err != nil {
+		var tce *types.TransactionCanceledException
+		if errors.As(err, &tce) {
+			for i, reason := range tce.CancellationReasons {
+				switch code := aws.ToString(reason.Code); {
+				case i >= processedStart && code == "ConditionalCheckFailed":
+					return nil, ErrDuplicateEvent
+				case code == "ConditionalCheckFailed" || code == "TransactionConflict":
+					// 읽은 뒤 상품이 수정/삭제된 경우 다시 읽어서 재시도
+					return nil, errTransactionConflict
+				}
+			}
+			return nil, fmt.Errorf("transaction cancelled: %w", tce)
+		}
+		return nil, fmt.Errorf("failed to transact write items: %w", err)
+	}
+
+	return results, nil
+}
+
+// deductionCancellationError - 트랜잭션 취소 사유를 실패한 상품 에러로 변환
+// processedStart 이후 항목은 처리 완료 기록 Put이다.
+func deductionCancellationError(tce *types.TransactionCanceledException, items []domain.StockDeductionItem, processedStart int) error {
+	for i, reason := range tce.CancellationReasons {
+		if i >= processedStart && aws.ToString(reason.Code) == "ConditionalCheckFailed" {
+			return ErrDuplicateEvent
+		}
+		if i >= len(items) {
+			continue
+		}
+		switch aws.ToString(reason.Code) {
+		case "ConditionalCheckFailed":
+			if len(reason.Item) == 0 {
+				return &domain.StockItemError{ProductID: items[i].ProductID, Err: ErrProductNotFound}
+			}
+			var current domain.Product
+			if err := attributevalue.UnmarshalMap(reason.Item, &current); err == nil && current.Stock < items[i].Quantity {
+				return &domain.StockItemError{ProductID: items[i].ProductID, Available: current.Stock, Err: ErrInsufficientStock}
+			}
+			// 재고는 충분하지만 읽은 뒤 버전이 바뀐 경우
+			return errTransactionConflict
+		case "TransactionConflict":
+			return errTransactionConflict
+		}
+	}
+	return fmt.Errorf("transaction cancelled: %w", tce)
+}
+
+func productKey(productID string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"product_id": &types.AttributeValueMemberS{Value: productID},
+	}
+}
+
+// versionCondition - 상품이 존재하고 version이 expectedVersion과 같을 때만 쓰기 허용
+// version 속성이 없는 기존 항목은 버전 0으로 취급한다.
+func versionCondition(expectedVersion int64) expression.ConditionBuilder {
+	version := expression.Name("version").Equal(expression.Value(expectedVersion))
+	if expectedVersion == 0 {
+		version = version.Or(expression.AttributeNotExists(expression.Name("version")))
+	}
+	return expression.AttributeExists(expression.Name("product_id")).And(version)
+}
+
+// conditionFailure - 조건 실패 시 반환된 기존 항목 유무로 미존재/버전 충돌 구분
+func conditionFailure(ccf *types.ConditionalCheckFailedException) error {
+	if len(ccf.Item) == 0 {
+		return ErrProductNotFound
+	}
+	return ErrVersionConflict
+}
@@ -0,0 +1,443 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cloud-wave-best-zizon/product-service/internal/domain"
+)
+
+var _ Store = (*MemoryProductStore)(nil)
+
+// MemoryProductStore - 로컬 모드용 인메모리 저장소
+type MemoryProductStore struct {
+	products     map[string]*domain.Product
+	reservations map[string]*domain.Reservation
+	outbox       map[string]*domain.OutboxMessage
+	// 처리 완료 기록 (키 -> 기록, ExpiresAt 이후 무시)
+	processed   map[string]*domain.ProcessedEvent
+	lastPurgeAt time.Time
+	mu          sync.RWMutex
+}
+
+func NewMemoryProductStore() *MemoryProductStore {
+	return &MemoryProductStore{
+		products:     make(map[string]*domain.Product),
+		reservations: make(map[string]*domain.Reservation),
+		outbox:       make(map[string]*domain.OutboxMessage),
+		processed:    make(map[string]*domain.ProcessedEvent),
+	}
+}
+
+func (s *MemoryProductStore) CreateProduct(ctx context.Context, product *domain.Product) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.products[product.ProductID]; exists {
+		return ErrProductAlreadyExists
+	}
+
+	productCopy := *product
+	s.products[product.ProductID] = &productCopy
+	return nil
+}
+
+func (s *MemoryProductStore) GetProduct(ctx context.Context, productID string) (*domain.Product, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	product, exists := s.products[productID]
+	if !exists {
+		return nil, ErrProductNotFound
+	}
+
+	// 깊은 복사를 위해 새 객체 생성
+	productCopy := *product
+	return &productCopy, nil
+}
+
+func (s *MemoryProductStore) UpdateProduct(ctx context.Context, product *domain.Product, expectedVersion int64) (*domain.Product, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, exists := s.products[product.ProductID]
+	if !exists {
+		return nil, ErrProductNotFound
+	}
+	if existing.Version != expectedVersion {
+		return nil, ErrVersionConflict
+	}
+
+	existing.Name = product.Name
+	existing.Price = product.Price
+	existing.Stock = product.Stock
+	existing.UpdatedAt = product.UpdatedAt
+	existing.Version++
+
+	productCopy := *existing
+	return &productCopy, nil
+}
+
+func (s *MemoryProductStore) DeleteProduct(ctx context.Context, productID string, expectedVersion int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, exists := s.products[productID]
+	if !exists {
+		return ErrProductNotFound
+	}
+	if existing.Version != expectedVersion {
+		return ErrVersionConflict
+	}
+
+	delete(s.products, productID)
+	return nil
+}
+
+func (s *MemoryProductStore) ListProducts(ctx context.Context, limit int, cursor string) ([]*domain.Product, string, error) {
+	limit = listLimit(limit)
+	after, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// 키 순서로 정렬하여 페이지 간 순서를 안정적으로 유지
+	ids := make([]string, 0, len(s.products))
+	for id := range s.products {
+		if id > after {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	nextCursor := ""
+	if len(ids) > limit {
+		ids = ids[:limit]
+		nextCursor = encodeCursor(ids[limit-1])
+	}
+
+	products := make([]*domain.Product, 0, len(ids))
+	for _, id := range ids {
+		productCopy := *s.products[id]
+		products = append(products, &productCopy)
+	}
+
+	return products, nextCursor, nil
+}
+
+func (s *MemoryProductStore) DeductStockBatch(ctx context.Context, deduction *StockDeduction) ([]domain.StockDeductionResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if deduction.Processed != nil && s.isProcessedLocked(deduction.Processed, now) {
+		return nil, ErrDuplicateEvent
+	}
+
+	// 모든 항목을 검증한 뒤에만 반영 (all-or-nothing)
+	items := deduction.Items
+	for _, item := range items {
+		product, exists := s.products[item.ProductID]
+		if !exists {
+			return nil, &domain.StockItemError{ProductID: item.ProductID, Err: ErrProductNotFound}
+		}
+		if product.Stock < item.Quantity {
+			return nil, &domain.StockItemError{ProductID: item.ProductID, Available: product.Stock, Err: ErrInsufficientStock}
+		}
+	}
+
+	results := make([]domain.StockDeductionResponse, 0, len(items))
+	for _, item := range items {
+		product := s.products[item.ProductID]
+		results = append(results, domain.StockDeductionResponse{
+			ProductID:     item.ProductID,
+			PreviousStock: product.Stock,
+			NewStock:      product.Stock - item.Quantity,
+			Deducted:      item.Quantity,
+		})
+	}
+
+	messages, err := deduction.outboxMessages(results)
+	if err != nil {
+		return nil, err
+	}
+
+	// 같은 임계 구역에서 재고, 아웃박스, 처리 완료 기록을 함께 반영
+	for _, result := range results {
+		product := s.products[result.ProductID]
+		product.Stock = result.NewStock
+		product.UpdatedAt = now
+		product.Version++
+	}
+	for _, message := range messages {
+		messageCopy := *message
+		s.outbox[message.MessageID] = &messageCopy
+	}
+	if deduction.Processed != nil {
+		s.markProcessedLocked(deduction.Processed, now)
+	}
+
+	return results, nil
+}
+
+func (s *MemoryProductStore) RestockBatch(ctx context.Context, restock *StockRestock) ([]domain.StockRestockResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if restock.Processed != nil && s.isProcessedLocked(restock.Processed, now) {
+		return nil, ErrDuplicateEvent
+	}
+
+	results := make([]domain.StockRestockResponse, 0, len(restock.Items))
+	for _, item := range restock.Items {
+		product, exists := s.products[item.ProductID]
+		if !exists {
+			continue
+		}
+		results = append(results, domain.StockRestockResponse{
+			ProductID:     item.ProductID,
+			PreviousStock: product.Stock,
+			NewStock:      product.Stock + item.Quantity,
+			Restocked:     item.Quantity,
+		})
+	}
+
+	messages, err := restock.outboxMessages(results)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, result := range results {
+		product := s.products[result.ProductID]
+		product.Stock = result.NewStock
+		product.UpdatedAt = now
+		product.Version++
+	}
+	for _, message := range messages {
+		messageCopy := *message
+		s.outbox[message.MessageID] = &messageCopy
+	}
+	if restock.Processed != nil {
+		s.markProcessedLocked(restock.Processed, now)
+	}
+
+	return results, nil
+}
+
+func (s *MemoryProductStore) GetProcessedOrder(ctx context.Context, orderID int) (*domain.ProcessedEvent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	processed, exists := s.processed[orderProcessedKey(domain.ProcessedActionDeduct, orderID)]
+	if !exists || !time.Now().Before(processed.ExpiresAt) {
+		return nil, ErrProcessedEventNotFound
+	}
+
+	processedCopy := *processed
+	return &processedCopy, nil
+}
+
+func (s *MemoryProductStore) RecordOutcome(ctx context.Context, processed *domain.ProcessedEvent, messages []*domain.OutboxMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if processed != nil && s.isProcessedLocked(processed, now) {
+		return ErrDuplicateEvent
+	}
+
+	for _, message := range messages {
+		messageCopy := *message
+		s.outbox[message.MessageID] = &messageCopy
+	}
+	if processed != nil {
+		s.markProcessedLocked(processed, now)
+	}
+	return nil
+}
+
+func (s *MemoryProductStore) isProcessedLocked(processed *domain.ProcessedEvent, now time.Time) bool {
+	for _, key := range processedKeys(processed) {
+		if record, exists := s.processed[key]; exists && now.Before(record.ExpiresAt) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *MemoryProductStore) markProcessedLocked(processed *domain.ProcessedEvent, now time.Time) {
+	// 만료된 기록은 1분에 한 번씩 정리
+	if now.Sub(s.lastPurgeAt) > time.Minute {
+		for key, record := range s.processed {
+			if !now.Before(record.ExpiresAt) {
+				delete(s.processed, key)
+			}
+		}
+		s.lastPurgeAt = now
+	}
+
+	record := *processed
+	for _, key := range processedKeys(processed) {
+		s.processed[key] = &record
+	}
+}
+
+func (s *MemoryProductStore) CreateReservation(ctx context.Context, reservation *domain.Reservation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	product, exists := s.products[reservation.ProductID]
+	if !exists {
+		return &domain.StockItemError{ProductID: reservation.ProductID, Err: ErrProductNotFound}
+	}
+	if product.Stock < reservation.Quantity {
+		return &domain.StockItemError{ProductID: reservation.ProductID, Available: product.Stock, Err: ErrInsufficientStock}
+	}
+
+	product.Stock -= reservation.Quantity
+	product.Reserved += reservation.Quantity
+	product.UpdatedAt = reservation.CreatedAt
+	product.Version++
+
+	reservationCopy := *reservation
+	s.reservations[reservation.ReservationID] = &reservationCopy
+	return nil
+}
+
+func (s *MemoryProductStore) GetReservation(ctx context.Context, reservationID string) (*domain.Reservation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	reservation, exists := s.reservations[reservationID]
+	if !exists {
+		return nil, ErrReservationNotFound
+	}
+
+	reservationCopy := *reservation
+	return &reservationCopy, nil
+}
+
+func (s *MemoryProductStore) ConfirmReservation(ctx context.Context, reservationID string, now time.Time) (*domain.Reservation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reservation, err := s.pendingReservation(reservationID)
+	if err != nil {
+		return nil, err
+	}
+	if !now.Before(reservation.ExpiresAt) {
+		return nil, ErrReservationExpired
+	}
+
+	if product, exists := s.products[reservation.ProductID]; exists {
+		product.Reserved -= reservation.Quantity
+		product.UpdatedAt = now
+		product.Version++
+	}
+
+	reservation.Status = domain.ReservationConfirmed
+	reservation.UpdatedAt = now
+
+	reservationCopy := *reservation
+	return &reservationCopy, nil
+}
+
+func (s *MemoryProductStore) ReleaseReservation(ctx context.Context, reservationID string, status domain.ReservationStatus) (*domain.Reservation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reservation, err := s.pendingReservation(reservationID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	// 상품이 삭제된 경우 예약 상태만 변경
+	if product, exists := s.products[reservation.ProductID]; exists {
+		product.Stock += reservation.Quantity
+		product.Reserved -= reservation.Quantity
+		product.UpdatedAt = now
+		product.Version++
+	}
+
+	reservation.Status = status
+	reservation.UpdatedAt = now
+
+	reservationCopy := *reservation
+	return &reservationCopy, nil
+}
+
+func (s *MemoryProductStore) ListExpiredReservations(ctx context.Context, now time.Time, limit int) ([]*domain.Reservation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	expired := make([]*domain.Reservation, 0)
+	for _, reservation := range s.reservations {
+		if len(expired) >= limit {
+			break
+		}
+		if reservation.Status == domain.ReservationPending && !now.Before(reservation.ExpiresAt) {
+			reservationCopy := *reservation
+			expired = append(expired, &reservationCopy)
+		}
+	}
+	return expired, nil
+}
+
+// pendingReservation - 호출자가 잠금을 보유한 상태에서 pending 예약 조회
+func (s *MemoryProductStore) pendingReservation(reservationID string) (*domain.Reservation, error) {
+	reservation, exists := s.reservations[reservationID]
+	if !exists {
+		return nil, ErrReservationNotFound
+	}
+	if reservation.Status != domain.ReservationPending {
+		return nil, ErrReservationNotPending
+	}
+	return reservation, nil
+}
+
+func (s *MemoryProductStore) ListPendingOutbox(ctx context.Context, limit int) ([]*domain.OutboxMessage, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	pending := make([]*domain.OutboxMessage, 0, len(s.outbox))
+	for _, message := range s.outbox {
+		if message.Status == domain.OutboxPending {
+			messageCopy := *message
+			pending = append(pending, &messageCopy)
+		}
+	}
+	sortOutbox(pending)
+
+	if len(pending) > limit {
+		pending = pending[:limit]
+	}
+	return pending, nil
+}
+
+// MarkOutboxSent - 로컬 모드에서는 보존할 필요가 없으므로 발행된 메시지를 삭제
+func (s *MemoryProductStore) MarkOutboxSent(ctx context.Context, messageID string, sentAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.outbox, messageID)
+	return nil
+}
+
+func (s *MemoryProductStore) MarkOutboxFailed(ctx context.Context, messageID string, attempts int, nextAttemptAt time.Time, lastErr string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if message, exists := s.outbox[messageID]; exists {
+		message.Attempts = attempts
+		message.NextAttemptAt = nextAttemptAt
+		message.LastError = lastErr
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/cloud-wave-best-zizon/product-service/internal/domain"
+)
+
+// 발행 완료된 아웃박스 메시지 보존 기간 (DynamoDB TTL)
+const sentOutboxRetention = 24 * time.Hour
+
+// OutboxStore - 트랜잭셔널 아웃박스 저장소
+// 메시지는 재고 쓰기와 같은 트랜잭션에서 기록되고, 릴레이가 발행 후 완료 처리한다.
+type OutboxStore interface {
+	// ListPendingOutbox - 미발행 메시지를 생성 순으로 최대 limit개 조회 (재시도 대기 중인 메시지 포함)
+	ListPendingOutbox(ctx context.Context, limit int) ([]*domain.OutboxMessage, error)
+	MarkOutboxSent(ctx context.Context, messageID string, sentAt time.Time) error
+	// MarkOutboxFailed - 발행 실패를 기록하고 nextAttemptAt 이후에 재시도하도록 예약
+	MarkOutboxFailed(ctx context.Context, messageID string, attempts int, nextAttemptAt time.Time, lastErr string) error
+}
+
+// StockDeduction - 한 트랜잭션으로 반영할 재고 차감과 부수 기록
+type StockDeduction struct {
+	Items []domain.StockDeductionItem
+	// Outbox - 차감 결과로 발행할 메시지를 만들어 같은 트랜잭션에 기록 (nil이면 기록하지 않음)
+	Outbox func(results []domain.StockDeductionResponse) ([]*domain.OutboxMessage, error)
+	// Processed - 처리 완료 기록을 같은 트랜잭션에 남김 (이미 있으면 ErrDuplicateEvent, nil이면 기록하지 않음)
+	Processed *domain.ProcessedEvent
+}
+
+func (d *StockDeduction) outboxMessages(results []domain.StockDeductionResponse) ([]*domain.OutboxMessage, error) {
+	if d.Outbox == nil {
+		return nil, nil
+	}
+	return d.Outbox(results)
+}
+
+// StockRestock - 한 트랜잭션으로 반영할 재고 복구와 부수 기록
+// 복구 시점에 삭제된 상품은 건너뛰고 결과에서 제외된다.
+type StockRestock struct {
+	Items     []domain.StockDeductionItem
+	Outbox    func(results []domain.StockRestockResponse) ([]*domain.OutboxMessage, error)
+	Processed *domain.ProcessedEvent
+}
+
+func (r *StockRestock) outboxMessages(results []domain.StockRestockResponse) ([]*domain.OutboxMessage, error) {
+	if r.Outbox == nil {
+		return nil, nil
+	}
+	return r.Outbox(results)
+}
+
+// sortOutbox - 생성 순 (같으면 ID 순) 정렬
+func sortOutbox(messages []*domain.OutboxMessage) {
+	sort.Slice(messages, func(i, j int) bool {
+		if messages[i].CreatedAt.Equal(messages[j].CreatedAt) {
+			return messages[i].MessageID < messages[j].MessageID
+		}
+		return messages[i].CreatedAt.Before(messages[j].CreatedAt)
+	})
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cloud-wave-best-zizon/product-service/internal/domain"
+)
+
+var ErrProcessedEventNotFound = errors.New("processed event not found")
+
+// ProcessedEventStore - 이벤트 처리 기록 조회, 재고 변경 없이 처리 결과만 기록 (실패 응답 등)
+type ProcessedEventStore interface {
+	// GetProcessedOrder - 주문 생성 이벤트의 처리 기록 (없거나 만료되면 ErrProcessedEventNotFound)
+	GetProcessedOrder(ctx context.Context, orderID int) (*domain.ProcessedEvent, error)
+	// RecordOutcome - 처리 완료 기록과 아웃박스 메시지를 원자적으로 기록
+	// processed가 이미 처리된 이벤트/주문이면 ErrDuplicateEvent (processed가 nil이면 중복 검사 없이 메시지만 기록)
+	RecordOutcome(ctx context.Context, processed *domain.ProcessedEvent, messages []*domain.OutboxMessage) error
+}
+
+// processedKeys - 처리 완료 기록의 키 (이벤트 ID와 주문 ID 각각)
+func processedKeys(processed *domain.ProcessedEvent) []string {
+	keys := make([]string, 0, 2)
+	if processed.EventID != "" {
+		keys = append(keys, "event#"+processed.EventID)
+	}
+	if processed.OrderID != 0 {
+		keys = append(keys, orderProcessedKey(processed.Action, processed.OrderID))
+	}
+	return keys
+}
+
+// orderProcessedKey - 주문 ID 기록 키 (차감은 order#, 복구는 restock#)
+func orderProcessedKey(action string, orderID int) string {
+	if action == domain.ProcessedActionRestock {
+		return fmt.Sprintf("restock#%d", orderID)
+	}
+	return fmt.Sprintf("order#%d", orderID)
+}
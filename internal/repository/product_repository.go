@@ -2,37 +2,72 @@ package repository
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
-	"sync"
-	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
-	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
-	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
-	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/cloud-wave-best-zizon/product-service/internal/domain"
 	pkgconfig "github.com/cloud-wave-best-zizon/product-service/pkg/config"
+	"github.com/cloud-wave-best-zizon/product-service/pkg/metrics"
+	"github.com/cloud-wave-best-zizon/product-service/pkg/tracing"
 )
 
 var (
     ErrProductNotFound        = errors.New("product not found")
     ErrInsufficientStock      = errors.New("insufficient stock")
     ErrProductAlreadyExists   = errors.New("product already exists")
+    ErrInvalidCursor          = errors.New("invalid cursor")
+    ErrVersionConflict        = errors.New("product version conflict")
+    ErrDuplicateEvent         = domain.ErrDuplicateEvent
 )
 
-type ProductRepository struct {
-	client    *dynamodb.Client
-	tableName string
-	localMode bool
-	// 로컬 모드용 인메모리 저장소
-	localStore map[string]*domain.Product
-	mu         sync.RWMutex
+// ProductStore - 상품 저장소 백엔드 인터페이스 (인메모리, DynamoDB 등)
+// 모든 쓰기는 상품의 version을 1씩 증가시킨다.
+type ProductStore interface {
+	CreateProduct(ctx context.Context, product *domain.Product) error
+	GetProduct(ctx context.Context, productID string) (*domain.Product, error)
+	// UpdateProduct - 저장된 version이 expectedVersion과 같을 때만 이름/가격/재고를 갱신 (compare-and-swap)
+	// 없으면 ErrProductNotFound, 버전이 다르면 ErrVersionConflict
+	UpdateProduct(ctx context.Context, product *domain.Product, expectedVersion int64) (*domain.Product, error)
+	DeleteProduct(ctx context.Context, productID string, expectedVersion int64) error
+	// ListProducts - 최대 limit개의 상품과 다음 페이지 커서를 반환 (마지막 페이지면 빈 문자열, limit이 0 이하면 DefaultListLimit)
+	ListProducts(ctx context.Context, limit int, cursor string) ([]*domain.Product, string, error)
+	// DeductStockBatch - 모든 항목과 아웃박스 메시지를 원자적으로 반영 (하나라도 실패하면 아무것도 반영하지 않음)
+	// 실패한 상품은 *domain.StockItemError로 반환하며, Items의 ProductID는 중복되지 않아야 한다.
+	DeductStockBatch(ctx context.Context, deduction *StockDeduction) ([]domain.StockDeductionResponse, error)
+	// RestockBatch - 수량을 재고에 되돌리고 아웃박스/처리 완료 기록을 원자적으로 반영 (이미 복구했으면 ErrDuplicateEvent)
+	RestockBatch(ctx context.Context, restock *StockRestock) ([]domain.StockRestockResponse, error)
 }
 
+// Store - 하나의 백엔드가 제공하는 전체 저장소
+type Store interface {
+	ProductStore
+	ReservationStore
+	OutboxStore
+	ProcessedEventStore
+}
+
+// DefaultListLimit - ListProducts에 0 이하의 limit이 전달됐을 때의 페이지 크기
+const DefaultListLimit = 20
+
+// listLimit - 0 이하의 limit을 기본 페이지 크기로 보정
+func listLimit(limit int) int {
+	if limit <= 0 {
+		return DefaultListLimit
+	}
+	return limit
+}
+
+// MaxBatchItems - 한 번에 차감 가능한 최대 상품 수
+// DynamoDB TransactWriteItems는 100개 항목까지 허용하며, 상품마다 아웃박스 메시지가 하나씩 함께 기록되고
+// 처리 완료 기록 등 부수 항목을 위한 여유를 남겨둔다.
+const MaxBatchItems = 45
+
 func NewDynamoDBClient(cfg *pkgconfig.Config) (*dynamodb.Client, error) {
 	if cfg.LocalMode && cfg.DynamoDBEndpoint == "" {
 		// 인메모리 모드
@@ -76,219 +111,37 @@ func NewDynamoDBClient(cfg *pkgconfig.Config) (*dynamodb.Client, error) {
 		return nil, fmt.Errorf("failed to load AWS config: %w", err)
 	}
 
-	return dynamodb.NewFromConfig(awsCfg), nil
-}
-
-func NewProductRepository(client *dynamodb.Client, tableName string) *ProductRepository {
-	return &ProductRepository{
-		client:     client,
-		tableName:  tableName,
-		localMode:  client == nil,
-		localStore: make(map[string]*domain.Product),
-	}
-}
-
-// CreateTableIfNotExists - DynamoDB Local 사용 시 테이블 생성
-func (r *ProductRepository) CreateTableIfNotExists(ctx context.Context) error {
-	if !r.localMode {
-		return nil
-	}
-
-	// 테이블 존재 확인
-	_, err := r.client.DescribeTable(ctx, &dynamodb.DescribeTableInput{
-		TableName: aws.String(r.tableName),
-	})
-
-	if err == nil {
-		// 테이블이 이미 존재함
-		return nil
-	}
-
-	// 테이블 생성
-	_, err = r.client.CreateTable(ctx, &dynamodb.CreateTableInput{
-		TableName: aws.String(r.tableName),
-		KeySchema: []types.KeySchemaElement{
-			{
-				AttributeName: aws.String("product_id"),
-				KeyType:       types.KeyTypeHash,
-			},
-		},
-		AttributeDefinitions: []types.AttributeDefinition{
-			{
-				AttributeName: aws.String("product_id"),
-				AttributeType: types.ScalarAttributeTypeS,
-			},
-		},
-		BillingMode: types.BillingModePayPerRequest,
-	})
-
-	if err != nil {
-		return fmt.Errorf("failed to create table: %w", err)
-	}
-
-	// 테이블이 활성화될 때까지 대기
-	waiter := dynamodb.NewTableExistsWaiter(r.client)
-	err = waiter.Wait(ctx, &dynamodb.DescribeTableInput{
-		TableName: aws.String(r.tableName),
-	}, 30*time.Second)
-
-	return err
+	return dynamodb.NewFromConfig(awsCfg, func(o *dynamodb.Options) {
+		o.APIOptions = append(o.APIOptions, metrics.DynamoDBAPIOption, tracing.DynamoDBAPIOption)
+	}), nil
 }
 
-func (r *ProductRepository) CreateProduct(ctx context.Context, product *domain.Product) error {
-	if r.localMode {
-		r.mu.Lock()
-		defer r.mu.Unlock()
-
-		if _, exists := r.localStore[product.ProductID]; exists {
-			return ErrProductAlreadyExists
-		}
-
-		r.localStore[product.ProductID] = product
-		return nil
-	}
-
-	av, err := attributevalue.MarshalMap(product)
-	if err != nil {
-		return fmt.Errorf("failed to marshal product: %w", err)
-	}
-
-	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
-		TableName: aws.String(r.tableName),
-		Item:      av,
-		ConditionExpression: aws.String("attribute_not_exists(product_id)"),
-	})
-
-	if err != nil {
-		var ccf *types.ConditionalCheckFailedException
-        if errors.As(err, &ccf) {
-    		return ErrProductAlreadyExists
-        }
-		return fmt.Errorf("failed to put item: %w", err)
-	}
-
-	return nil
+// 페이지 커서 - 마지막으로 읽은 키를 base64로 인코딩한 불투명 토큰
+type pageCursor struct {
+	ProductID string `json:"product_id"`
 }
 
-func (r *ProductRepository) GetProduct(ctx context.Context, productID string) (*domain.Product, error) {
-	if r.localMode {
-		r.mu.RLock()
-		defer r.mu.RUnlock()
-
-		product, exists := r.localStore[productID]
-		if !exists {
-			return nil, ErrProductNotFound
-		}
-
-		// 깊은 복사를 위해 새 객체 생성
-		productCopy := *product
-		return &productCopy, nil
+func encodeCursor(productID string) string {
+	if productID == "" {
+		return ""
 	}
-
-	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
-		TableName: aws.String(r.tableName),
-		Key: map[string]types.AttributeValue{
-			"product_id": &types.AttributeValueMemberS{Value: productID},
-		},
-	})
-
-	if err != nil {
-		return nil, fmt.Errorf("failed to get item: %w", err)
-	}
-
-	if result.Item == nil {
-		return nil, ErrProductNotFound
-	}
-
-	var product domain.Product
-	if err := attributevalue.UnmarshalMap(result.Item, &product); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal product: %w", err)
-	}
-
-	return &product, nil
+	b, _ := json.Marshal(pageCursor{ProductID: productID})
+	return base64.RawURLEncoding.EncodeToString(b)
 }
 
-func (r *ProductRepository) DeductStock(ctx context.Context, productID string, quantity int) (newStock int, previousStock int, err error) {
-	if r.localMode {
-		r.mu.Lock()
-		defer r.mu.Unlock()
-
-		product, exists := r.localStore[productID]
-		if !exists {
-			return 0, 0, ErrProductNotFound
-		}
-
-		previousStock = product.Stock
-
-		if product.Stock < quantity {
-			return 0, previousStock, ErrInsufficientStock
-		}
-
-		product.Stock -= quantity
-		product.UpdatedAt = time.Now()
-
-		return product.Stock, previousStock, nil
+func decodeCursor(cursor string) (string, error) {
+	if cursor == "" {
+		return "", nil
 	}
 
-	// Get current stock first
-	product, err := r.GetProduct(ctx, productID)
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
 	if err != nil {
-		return 0, 0, err
+		return "", ErrInvalidCursor
 	}
-	previousStock = product.Stock
 
-	// Atomic update with condition
-	update := expression.Set(
-		expression.Name("stock"),
-		expression.Minus(
-			expression.Name("stock"),
-			expression.Value(quantity),
-		),
-	).Set(
-		expression.Name("updated_at"),
-		expression.Value(time.Now()),
-	)
-
-	// 재고가 충분한 경우에만 업데이트
-	condition := expression.GreaterThanEqual(
-		expression.Name("stock"),
-		expression.Value(quantity),
-	)
-
-	expr, err := expression.NewBuilder().
-		WithUpdate(update).
-		WithCondition(condition).
-		Build()
-	if err != nil {
-		return 0, previousStock, err
+	var c pageCursor
+	if err := json.Unmarshal(b, &c); err != nil || c.ProductID == "" {
+		return "", ErrInvalidCursor
 	}
-
-	input := &dynamodb.UpdateItemInput{
-		TableName: aws.String(r.tableName),
-		Key: map[string]types.AttributeValue{
-			"product_id": &types.AttributeValueMemberS{Value: productID},
-		},
-		ExpressionAttributeNames:  expr.Names(),
-		ExpressionAttributeValues: expr.Values(),
-		UpdateExpression:          expr.Update(),
-		ConditionExpression:       expr.Condition(),
-		ReturnValues:              types.ReturnValueAllNew,
-	}
-
-	result, err := r.client.UpdateItem(ctx, input)
-	if err != nil {
-		var ccf *types.ConditionalCheckFailedException
-		if errors.As(err, &ccf) {
-			return 0, previousStock, ErrInsufficientStock
-		}
-		return 0, previousStock, err
-	}
-
-	// 업데이트된 재고 반환
-	var updatedProduct domain.Product
-	if err := attributevalue.UnmarshalMap(result.Attributes, &updatedProduct); err != nil {
-		return 0, previousStock, err
-	}
-
-	return updatedProduct.Stock, previousStock, nil
+	return c.ProductID, nil
 }
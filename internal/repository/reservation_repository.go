@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cloud-wave-best-zizon/product-service/internal/domain"
+)
+
+var (
+	ErrReservationNotFound   = errors.New("reservation not found")
+	ErrReservationNotPending = errors.New("reservation is not pending")
+	ErrReservationExpired    = errors.New("reservation expired")
+)
+
+// ReservationStore - 재고 예약 저장소
+// 예약 상태 변경과 상품 재고(stock/reserved) 변경은 항상 원자적으로 함께 반영된다.
+type ReservationStore interface {
+	// CreateReservation - 상품 재고를 예약 수량만큼 reserved로 옮기고 예약을 저장
+	// 상품 관련 실패는 *domain.StockItemError로 반환
+	CreateReservation(ctx context.Context, reservation *domain.Reservation) error
+	GetReservation(ctx context.Context, reservationID string) (*domain.Reservation, error)
+	// ConfirmReservation - now 기준 만료되지 않은 pending 예약을 확정하고 reserved 수량을 소진
+	ConfirmReservation(ctx context.Context, reservationID string, now time.Time) (*domain.Reservation, error)
+	// ReleaseReservation - pending 예약을 status(released/expired)로 전환하고 reserved 수량을 stock으로 복구
+	ReleaseReservation(ctx context.Context, reservationID string, status domain.ReservationStatus) (*domain.Reservation, error)
+	// ListExpiredReservations - now 이전에 만료된 pending 예약을 최대 limit개 조회
+	ListExpiredReservations(ctx context.Context, now time.Time, limit int) ([]*domain.Reservation, error)
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cloud-wave-best-zizon/product-service/internal/domain"
+	"github.com/cloud-wave-best-zizon/product-service/internal/events"
+)
+
+// stockEventSchemaVersion - 발행하는 재고/사가 응답 이벤트의 스키마 버전
+const stockEventSchemaVersion = 1
+
+// newOutboxMessage - 이벤트를 codec으로 인코딩해 즉시 발행 가능한 아웃박스 메시지로 만든다.
+// codec에 스키마가 없는 이벤트는 JSON으로 인코딩하며, 실제 인코딩은 content-type 헤더에 남는다.
+// 표준 헤더는 origin(요청 ID)과 ctx의 현재 스팬(trace context)으로 채운다.
+func newOutboxMessage(ctx context.Context, codec events.Codec, messageID, topic, key, eventType string, origin domain.StockOrigin, event interface{}) (*domain.OutboxMessage, error) {
+	payload, used, err := events.EncodeEvent(codec, event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	now := time.Now()
+	return &domain.OutboxMessage{
+		MessageID:     messageID,
+		Topic:         topic,
+		Key:           key,
+		Payload:       payload,
+		Headers:       events.MessageHeaders(ctx, eventType, stockEventSchemaVersion, used.ContentType(), origin.RequestID),
+		Status:        domain.OutboxPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}, nil
+}
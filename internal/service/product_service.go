@@ -3,10 +3,20 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
+	"strconv"
 	"time"
 
 	"github.com/cloud-wave-best-zizon/product-service/internal/domain"
+	"github.com/cloud-wave-best-zizon/product-service/internal/events"
 	"github.com/cloud-wave-best-zizon/product-service/internal/repository"
+	"github.com/cloud-wave-best-zizon/product-service/pkg/logging"
+	"github.com/cloud-wave-best-zizon/product-service/pkg/metrics"
+	"github.com/cloud-wave-best-zizon/product-service/pkg/tracing"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
@@ -14,42 +24,91 @@ var (
 	ErrProductNotFound   = errors.New("product not found")
 	ErrProductExists     = errors.New("product already exists")
 	ErrInsufficientStock = errors.New("insufficient stock")
+	ErrInvalidPatch      = domain.ErrInvalidPatch
+	ErrInvalidCursor     = errors.New("invalid cursor")
+	ErrVersionConflict   = errors.New("product version conflict")
+	ErrInvalidBatch      = domain.ErrInvalidBatch
+	ErrDuplicateEvent    = domain.ErrDuplicateEvent
+	ErrInvalidProductID  = domain.ErrInvalidProductID
 )
 
+const (
+	DefaultListLimit = repository.DefaultListLimit
+	MaxListLimit     = 100
+)
+
+// ProductServiceConfig - 재고 변경 이벤트는 아웃박스를 거쳐 StockTopic으로,
+// 주문 이벤트 처리 결과(사가 응답)는 ReplyTopic으로 발행된다.
+// 토픽이 비어 있으면 해당 이벤트를 기록하지 않음 (Kafka 비활성화 시)
+type ProductServiceConfig struct {
+	StockTopic string
+	ReplyTopic string
+	// StockKeyStrategy - StockTopic 메시지 키 기준 (기본 상품 ID, 사가 응답은 항상 주문 ID)
+	StockKeyStrategy events.KeyStrategy
+	DedupeTTL        time.Duration // 처리한 주문 이벤트를 중복으로 판단하는 기간
+	Codec            events.Codec  // 발행 이벤트 인코딩 (nil이면 JSON, 스키마가 없는 이벤트도 JSON)
+}
+
 type ProductService struct {
-	productRepo *repository.ProductRepository
-	logger      *zap.Logger
+	productStore   repository.ProductStore
+	processedStore repository.ProcessedEventStore
+	stockTopic     string
+	replyTopic     string
+	stockKey       events.KeyStrategy
+	codec          events.Codec
+	dedupeTTL      time.Duration
+	logger         *zap.Logger
 }
 
-func NewProductService(productRepo *repository.ProductRepository, logger *zap.Logger) *ProductService {
+func NewProductService(productStore repository.ProductStore, processedStore repository.ProcessedEventStore, cfg ProductServiceConfig, logger *zap.Logger) *ProductService {
+	stockKey := cfg.StockKeyStrategy
+	if stockKey == "" {
+		stockKey = events.KeyByProductID
+	}
 	return &ProductService{
-		productRepo: productRepo,
-		logger:      logger,
+		productStore:   productStore,
+		processedStore: processedStore,
+		stockTopic:     cfg.StockTopic,
+		replyTopic:     cfg.ReplyTopic,
+		stockKey:       stockKey,
+		codec:          cfg.Codec,
+		dedupeTTL:      cfg.DedupeTTL,
+		logger:         logger,
 	}
 }
 
+// log - ctx의 요청 ID(request_id)를 붙인 로거
+func (s *ProductService) log(ctx context.Context) *zap.Logger {
+	return logging.FromContext(ctx, s.logger)
+}
+
 func (s *ProductService) CreateProduct(ctx context.Context, req domain.CreateProductRequest) (*domain.Product, error) {
+	productID, err := domain.NormalizeProductID(req.ProductID)
+	if err != nil {
+		return nil, err
+	}
 
 	product := &domain.Product{
-		ProductID: req.ProductID,
+		ProductID: productID,
 		Name:      req.Name,
 		Stock:     req.Stock,
 		Price:     req.Price,
+		Version:   1,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
 
-	if err := s.productRepo.CreateProduct(ctx, product); err != nil {
+	if err := s.productStore.CreateProduct(ctx, product); err != nil {
 		if errors.Is(err, repository.ErrProductAlreadyExists) {
-			        return nil, ErrProductExists
-			}
-		s.logger.Error("Failed to save product",
+			return nil, ErrProductExists
+		}
+		s.log(ctx).Error("Failed to save product",
 			zap.String("product_id", product.ProductID),
 			zap.Error(err))
 		return nil, err
 	}
 
-	s.logger.Info("Product created successfully",
+	s.log(ctx).Info("Product created successfully",
 		zap.String("product_id", product.ProductID),
 		zap.Int("initial_stock", product.Stock))
 
@@ -57,7 +116,7 @@ func (s *ProductService) CreateProduct(ctx context.Context, req domain.CreatePro
 }
 
 func (s *ProductService) GetProduct(ctx context.Context, productID string) (*domain.Product, error) {
-	product, err := s.productRepo.GetProduct(ctx, productID)
+	product, err := s.productStore.GetProduct(ctx, productID)
 	if err != nil {
 		if err == repository.ErrProductNotFound {
 			return nil, ErrProductNotFound
@@ -67,32 +126,543 @@ func (s *ProductService) GetProduct(ctx context.Context, productID string) (*dom
 	return product, nil
 }
 
-func (s *ProductService) DeductStock(ctx context.Context, productID string, quantity int) (*domain.StockDeductionResponse, error) {
-	// Atomic 재고 차감
-	newStock, previousStock, err := s.productRepo.DeductStock(ctx, productID, quantity)
-
-	result := &domain.StockDeductionResponse{
-		ProductID:     productID,
-		PreviousStock: previousStock,
-		NewStock:      newStock,
-		Deducted:      quantity,
+// ListProducts - 커서 기반 페이지 조회 (limit은 1~MaxListLimit 범위로 보정)
+func (s *ProductService) ListProducts(ctx context.Context, limit int, cursor string) ([]*domain.Product, string, error) {
+	if limit <= 0 {
+		limit = DefaultListLimit
+	}
+	if limit > MaxListLimit {
+		limit = MaxListLimit
 	}
 
+	products, nextCursor, err := s.productStore.ListProducts(ctx, limit, cursor)
 	if err != nil {
-		if err == repository.ErrProductNotFound {
-			return result, ErrProductNotFound
+		if errors.Is(err, repository.ErrInvalidCursor) {
+			return nil, "", ErrInvalidCursor
 		}
-		if err == repository.ErrInsufficientStock {
-			return result, ErrInsufficientStock
+		return nil, "", err
+	}
+	return products, nextCursor, nil
+}
+
+// UpdateProduct - expectedVersions(If-Match)가 주어지면 그중 하나가 현재 버전일 때만 갱신
+// 주어지지 않으면 현재 버전을 읽어 compare-and-swap으로 갱신한다.
+func (s *ProductService) UpdateProduct(ctx context.Context, productID string, req domain.UpdateProductRequest, expectedVersions []int64) (*domain.Product, error) {
+	version, err := s.resolveVersion(ctx, productID, expectedVersions)
+	if err != nil {
+		return nil, err
+	}
+
+	product := &domain.Product{
+		ProductID: productID,
+		Name:      req.Name,
+		Price:     *req.Price,
+		Stock:     *req.Stock,
+		UpdatedAt: time.Now(),
+	}
+
+	updated, err := s.productStore.UpdateProduct(ctx, product, version)
+	if err != nil {
+		return nil, s.writeError(ctx, err, productID, "Failed to update product")
+	}
+
+	s.log(ctx).Info("Product updated successfully",
+		zap.String("product_id", productID),
+		zap.Int64("version", updated.Version))
+
+	return updated, nil
+}
+
+// PatchProduct - JSON Merge Patch로 상품 일부 필드만 갱신
+func (s *ProductService) PatchProduct(ctx context.Context, productID string, patch []byte, expectedVersions []int64) (*domain.Product, error) {
+	product, err := s.GetProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if len(expectedVersions) > 0 && !slices.Contains(expectedVersions, product.Version) {
+		return nil, ErrVersionConflict
+	}
+	version := product.Version
+
+	if err := product.ApplyMergePatch(patch); err != nil {
+		return nil, err
+	}
+	product.UpdatedAt = time.Now()
+
+	updated, err := s.productStore.UpdateProduct(ctx, product, version)
+	if err != nil {
+		return nil, s.writeError(ctx, err, productID, "Failed to patch product")
+	}
+
+	s.log(ctx).Info("Product patched successfully",
+		zap.String("product_id", productID),
+		zap.Int64("version", updated.Version))
+
+	return updated, nil
+}
+
+func (s *ProductService) DeleteProduct(ctx context.Context, productID string, expectedVersions []int64) error {
+	version, err := s.resolveVersion(ctx, productID, expectedVersions)
+	if err != nil {
+		return err
+	}
+
+	if err := s.productStore.DeleteProduct(ctx, productID, version); err != nil {
+		return s.writeError(ctx, err, productID, "Failed to delete product")
+	}
+
+	s.log(ctx).Info("Product deleted successfully",
+		zap.String("product_id", productID))
+
+	return nil
+}
+
+// resolveVersion - 쓰기 조건으로 사용할 버전 (지정되지 않으면 현재 저장된 버전)
+// 기대 버전이 여러 개면 현재 버전이 그중 하나일 때 현재 버전을 조건으로 사용한다.
+func (s *ProductService) resolveVersion(ctx context.Context, productID string, expectedVersions []int64) (int64, error) {
+	if len(expectedVersions) == 1 {
+		return expectedVersions[0], nil
+	}
+
+	product, err := s.GetProduct(ctx, productID)
+	if err != nil {
+		return 0, err
+	}
+	if len(expectedVersions) > 0 && !slices.Contains(expectedVersions, product.Version) {
+		return 0, ErrVersionConflict
+	}
+	return product.Version, nil
+}
+
+func (s *ProductService) writeError(ctx context.Context, err error, productID, message string) error {
+	switch {
+	case errors.Is(err, repository.ErrProductNotFound):
+		return ErrProductNotFound
+	case errors.Is(err, repository.ErrVersionConflict):
+		return ErrVersionConflict
+	}
+
+	s.log(ctx).Error(message,
+		zap.String("product_id", productID),
+		zap.Error(err))
+	return err
+}
+
+func (s *ProductService) DeductStock(ctx context.Context, productID string, quantity int, origin domain.StockOrigin) (*domain.StockDeductionResponse, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "ProductService.DeductStock", trace.WithAttributes(
+		attribute.String("product_id", productID),
+		attribute.Int("order_id", origin.OrderID),
+	))
+	defer span.End()
+
+	// 일괄 차감과 같은 검증 (상품 ID 정규화, 수량 확인)
+	items, err := mergeDeductionItems([]domain.StockDeductionItem{{ProductID: productID, Quantity: quantity}})
+	if err != nil {
+		metrics.StockDeductions.WithLabelValues(metrics.StockOutcomeInvalid).Inc()
+		return nil, err
+	}
+	productID = items[0].ProductID
+
+	// Atomic 재고 차감
+	results, err := s.deductStock(ctx, items, origin, false)
+	if err != nil {
+		var itemErr *domain.StockItemError
+		if errors.As(err, &itemErr) {
+			return &domain.StockDeductionResponse{
+				ProductID:     productID,
+				PreviousStock: itemErr.Available,
+				Deducted:      quantity,
+			}, itemErr.Err
 		}
 		return nil, err
 	}
+	result := &results[0]
 
-	s.logger.Info("Stock deducted successfully",
+	s.log(ctx).Info("Stock deducted successfully",
 		zap.String("product_id", productID),
-		zap.Int("previous_stock", previousStock),
+		zap.Int("previous_stock", result.PreviousStock),
 		zap.Int("deducted", quantity),
-		zap.Int("new_stock", newStock))
+		zap.Int("new_stock", result.NewStock))
 
 	return result, nil
 }
+
+// DeductStockBatch - 주문의 모든 상품 재고를 한 번에 차감 (하나라도 실패하면 전체 미반영)
+// 실패한 상품은 *domain.StockItemError로 반환된다.
+func (s *ProductService) DeductStockBatch(ctx context.Context, items []domain.StockDeductionItem, origin domain.StockOrigin) ([]domain.StockDeductionResponse, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "ProductService.DeductStockBatch", trace.WithAttributes(
+		attribute.Int("order_id", origin.OrderID),
+		attribute.Int("items_count", len(items)),
+	))
+	defer span.End()
+
+	merged, err := mergeDeductionItems(items)
+	if err != nil {
+		metrics.StockDeductions.WithLabelValues(metrics.StockOutcomeInvalid).Inc()
+		return nil, err
+	}
+
+	results, err := s.deductStock(ctx, merged, origin, false)
+	if err != nil {
+		return nil, err
+	}
+
+	s.log(ctx).Info("Stock batch deducted successfully",
+		zap.Int("order_id", origin.OrderID),
+		zap.Int("items_count", len(results)))
+
+	return results, nil
+}
+
+// DeductStockForOrder - 주문 이벤트의 재고를 일괄 차감하고 결과를 사가 응답 이벤트(StockReserved/StockReservationFailed)로 기록
+// 상품 없음/재고 부족/잘못된 요청은 실패 응답을 기록한 뒤 원인 에러를 반환하며, 그 외 에러는 아무것도 기록하지 않는다.
+func (s *ProductService) DeductStockForOrder(ctx context.Context, items []domain.StockDeductionItem, origin domain.StockOrigin) ([]domain.StockDeductionResponse, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "ProductService.DeductStockForOrder", trace.WithAttributes(
+		attribute.Int("order_id", origin.OrderID),
+		attribute.String("event_id", origin.EventID),
+		attribute.Int("items_count", len(items)),
+	))
+	defer span.End()
+
+	merged, err := mergeDeductionItems(items)
+	if err == nil {
+		var results []domain.StockDeductionResponse
+		results, err = s.deductStock(ctx, merged, origin, true)
+		if err == nil {
+			s.log(ctx).Info("Stock deducted for order",
+				zap.Int("order_id", origin.OrderID),
+				zap.String("event_id", origin.EventID),
+				zap.Int("items_count", len(results)))
+			return results, nil
+		}
+	} else {
+		metrics.StockDeductions.WithLabelValues(metrics.StockOutcomeInvalid).Inc()
+	}
+
+	var itemErr *domain.StockItemError
+	if !errors.As(err, &itemErr) && !errors.Is(err, ErrInvalidBatch) {
+		return nil, err
+	}
+	if recordErr := s.recordReservationFailure(ctx, origin, err); recordErr != nil {
+		return nil, recordErr
+	}
+	return nil, err
+}
+
+// RestockForOrder - 주문 취소 시 그 주문에서 실제로 차감했던 수량만 재고로 되돌림 (주문당 한 번, 이후는 ErrDuplicateEvent)
+// 차감 기록이 없으면 (취소가 먼저 도착했거나 기록 만료) 주문 처리 기록만 남겨 뒤늦게 도착한 주문 생성 이벤트가 차감되지 않게 한다.
+func (s *ProductService) RestockForOrder(ctx context.Context, origin domain.StockOrigin) ([]domain.StockRestockResponse, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "ProductService.RestockForOrder", trace.WithAttributes(
+		attribute.Int("order_id", origin.OrderID),
+		attribute.String("event_id", origin.EventID),
+	))
+	defer span.End()
+
+	if origin.OrderID == 0 || origin.EventID == "" {
+		return nil, fmt.Errorf("%w: order_id and event_id are required for restock", ErrInvalidBatch)
+	}
+
+	deducted, err := s.processedStore.GetProcessedOrder(ctx, origin.OrderID)
+	if errors.Is(err, repository.ErrProcessedEventNotFound) {
+		err = s.processedStore.RecordOutcome(ctx, s.processedEvent(origin, domain.ProcessedActionDeduct, nil), nil)
+		if err == nil {
+			s.log(ctx).Info("Order cancelled before stock was deducted, nothing to restock",
+				zap.Int("order_id", origin.OrderID),
+				zap.String("event_id", origin.EventID))
+			return nil, nil
+		}
+		// 그 사이 주문 생성 이벤트가 처리되었으면 기록을 다시 읽는다
+		if errors.Is(err, repository.ErrDuplicateEvent) {
+			deducted, err = s.processedStore.GetProcessedOrder(ctx, origin.OrderID)
+		}
+	}
+	if err != nil {
+		s.log(ctx).Error("Failed to get processed order",
+			zap.Int("order_id", origin.OrderID),
+			zap.Error(err))
+		return nil, err
+	}
+
+	if len(deducted.Items) == 0 {
+		s.log(ctx).Info("No stock was deducted for order, nothing to restock",
+			zap.Int("order_id", origin.OrderID),
+			zap.String("event_id", origin.EventID))
+		return nil, nil
+	}
+
+	results, err := s.productStore.RestockBatch(ctx, &repository.StockRestock{
+		Items:     deducted.Items,
+		Outbox:    s.restockOutbox(ctx, origin),
+		Processed: s.processedEvent(origin, domain.ProcessedActionRestock, deducted.Items),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrDuplicateEvent):
+			return nil, ErrDuplicateEvent
+		case errors.Is(err, repository.ErrVersionConflict):
+			return nil, ErrVersionConflict
+		}
+		s.log(ctx).Error("Failed to restock order",
+			zap.Int("order_id", origin.OrderID),
+			zap.Error(err))
+		return nil, err
+	}
+
+	s.log(ctx).Info("Stock restocked for cancelled order",
+		zap.Int("order_id", origin.OrderID),
+		zap.String("event_id", origin.EventID),
+		zap.Int("items_count", len(results)))
+
+	return results, nil
+}
+
+// restockOutbox - 복구된 상품마다 StockRestockedEvent 아웃박스 메시지 생성
+func (s *ProductService) restockOutbox(ctx context.Context, origin domain.StockOrigin) func([]domain.StockRestockResponse) ([]*domain.OutboxMessage, error) {
+	if s.stockTopic == "" {
+		return nil
+	}
+
+	return func(results []domain.StockRestockResponse) ([]*domain.OutboxMessage, error) {
+		messages := make([]*domain.OutboxMessage, 0, len(results))
+		for _, result := range results {
+			event := events.StockRestockedEvent{
+				EventID:   uuid.New().String(),
+				EventType: events.EventTypeStockRestocked,
+				OrderID:   origin.OrderID,
+				ProductID: result.ProductID,
+				Quantity:  result.Restocked,
+				NewStock:  result.NewStock,
+				Timestamp: time.Now(),
+				RequestID: origin.RequestID,
+			}
+
+			key := events.MessageKey(s.stockKey, origin.OrderID, event.ProductID, event.EventID)
+			message, err := newOutboxMessage(ctx, s.codec, event.EventID, s.stockTopic, key, event.EventType, origin, event)
+			if err != nil {
+				return nil, err
+			}
+			messages = append(messages, message)
+		}
+		return messages, nil
+	}
+}
+
+// recordReservationFailure - 실패 응답과 처리 완료 기록을 함께 남김 (재전달 시 같은 응답을 중복 발행하지 않음)
+func (s *ProductService) recordReservationFailure(ctx context.Context, origin domain.StockOrigin, cause error) error {
+	var messages []*domain.OutboxMessage
+	if s.replyTopic != "" {
+		event := events.StockReservationFailedEvent{
+			EventID:     uuid.New().String(),
+			EventType:   events.EventTypeStockReservationFailed,
+			OrderID:     origin.OrderID,
+			CausationID: origin.EventID,
+			Reason:      events.ReasonInvalidRequest,
+			Message:     cause.Error(),
+			Timestamp:   time.Now(),
+			RequestID:   origin.RequestID,
+		}
+		var itemErr *domain.StockItemError
+		if errors.As(cause, &itemErr) {
+			event.ProductID = itemErr.ProductID
+			switch {
+			case errors.Is(itemErr.Err, ErrInsufficientStock):
+				event.Reason = events.ReasonInsufficientStock
+				event.Available = itemErr.Available
+			case errors.Is(itemErr.Err, ErrProductNotFound):
+				event.Reason = events.ReasonProductNotFound
+			}
+		}
+
+		message, err := newOutboxMessage(ctx, s.codec, event.EventID, s.replyTopic, strconv.Itoa(origin.OrderID), event.EventType, origin, event)
+		if err != nil {
+			return err
+		}
+		messages = append(messages, message)
+	}
+
+	if err := s.processedStore.RecordOutcome(ctx, s.processedEvent(origin, domain.ProcessedActionDeduct, nil), messages); err != nil {
+		if errors.Is(err, repository.ErrDuplicateEvent) {
+			return ErrDuplicateEvent
+		}
+		s.log(ctx).Error("Failed to record stock reservation failure",
+			zap.Int("order_id", origin.OrderID),
+			zap.Error(err))
+		return err
+	}
+
+	s.log(ctx).Warn("Stock reservation failed for order",
+		zap.Int("order_id", origin.OrderID),
+		zap.String("event_id", origin.EventID),
+		zap.Error(cause))
+	return nil
+}
+
+// deductStock - 재고 차감과 StockDeductedEvent 아웃박스 기록을 한 트랜잭션으로 반영
+// reply가 true면(사가 이벤트 처리) 사가 성공 응답(StockReservedEvent)과 처리 완료 기록도 같은 트랜잭션에 포함되어
+// 재전달된 이벤트/주문은 ErrDuplicateEvent가 된다. HTTP 차감은 같은 주문으로 나눠 호출될 수 있어 중복 검사를 하지 않는다.
+func (s *ProductService) deductStock(ctx context.Context, items []domain.StockDeductionItem, origin domain.StockOrigin, reply bool) (results []domain.StockDeductionResponse, err error) {
+	defer func() {
+		metrics.StockDeductions.WithLabelValues(stockOutcome(err)).Inc()
+	}()
+
+	var processed *domain.ProcessedEvent
+	if reply {
+		processed = s.processedEvent(origin, domain.ProcessedActionDeduct, items)
+	}
+	results, err = s.productStore.DeductStockBatch(ctx, &repository.StockDeduction{
+		Items:     items,
+		Outbox:    s.deductionOutbox(ctx, origin, reply),
+		Processed: processed,
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrDuplicateEvent) {
+			return nil, ErrDuplicateEvent
+		}
+		var itemErr *domain.StockItemError
+		if errors.As(err, &itemErr) {
+			switch {
+			case errors.Is(itemErr.Err, repository.ErrProductNotFound):
+				return nil, &domain.StockItemError{ProductID: itemErr.ProductID, Err: ErrProductNotFound}
+			case errors.Is(itemErr.Err, repository.ErrInsufficientStock):
+				return nil, &domain.StockItemError{ProductID: itemErr.ProductID, Available: itemErr.Available, Err: ErrInsufficientStock}
+			}
+		}
+		if errors.Is(err, repository.ErrVersionConflict) {
+			return nil, ErrVersionConflict
+		}
+		s.log(ctx).Error("Failed to deduct stock",
+			zap.Int("order_id", origin.OrderID),
+			zap.Int("items_count", len(items)),
+			zap.Error(err))
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// deductionOutbox - 차감된 상품마다 StockDeductedEvent, reply면 주문 단위 StockReservedEvent 아웃박스 메시지 생성
+func (s *ProductService) deductionOutbox(ctx context.Context, origin domain.StockOrigin, reply bool) func([]domain.StockDeductionResponse) ([]*domain.OutboxMessage, error) {
+	reply = reply && s.replyTopic != ""
+	if s.stockTopic == "" && !reply {
+		return nil
+	}
+
+	return func(results []domain.StockDeductionResponse) ([]*domain.OutboxMessage, error) {
+		messages := make([]*domain.OutboxMessage, 0, len(results)+1)
+		if s.stockTopic != "" {
+			for _, result := range results {
+				event := events.StockDeductedEvent{
+					EventID:   uuid.New().String(),
+					OrderID:   origin.OrderID,
+					ProductID: result.ProductID,
+					Quantity:  result.Deducted,
+					NewStock:  result.NewStock,
+					Timestamp: time.Now(),
+					RequestID: origin.RequestID,
+				}
+
+				key := events.MessageKey(s.stockKey, origin.OrderID, event.ProductID, event.EventID)
+				message, err := newOutboxMessage(ctx, s.codec, event.EventID, s.stockTopic, key, events.EventTypeStockDeducted, origin, event)
+				if err != nil {
+					return nil, err
+				}
+				messages = append(messages, message)
+			}
+		}
+
+		if reply {
+			event := events.StockReservedEvent{
+				EventID:     uuid.New().String(),
+				EventType:   events.EventTypeStockReserved,
+				OrderID:     origin.OrderID,
+				CausationID: origin.EventID,
+				Items:       make([]events.StockReservedItem, 0, len(results)),
+				Timestamp:   time.Now(),
+				RequestID:   origin.RequestID,
+			}
+			for _, result := range results {
+				event.Items = append(event.Items, events.StockReservedItem{
+					ProductID:     result.ProductID,
+					Quantity:      result.Deducted,
+					PreviousStock: result.PreviousStock,
+					NewStock:      result.NewStock,
+				})
+			}
+
+			// 응답은 주문 ID로 키를 지정해 같은 주문의 응답 순서를 보장
+			message, err := newOutboxMessage(ctx, s.codec, event.EventID, s.replyTopic, strconv.Itoa(origin.OrderID), event.EventType, origin, event)
+			if err != nil {
+				return nil, err
+			}
+			messages = append(messages, message)
+		}
+		return messages, nil
+	}
+}
+
+// processedEvent - 이벤트 기반 처리일 때 기록할 처리 완료 정보 (items는 실제로 차감한 수량)
+// 이벤트 ID가 없어도 주문 ID(order#<id>) 키로 중복을 막으며, 둘 다 없을 때만 기록하지 않는다.
+func (s *ProductService) processedEvent(origin domain.StockOrigin, action string, items []domain.StockDeductionItem) *domain.ProcessedEvent {
+	if origin.EventID == "" && origin.OrderID == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	return &domain.ProcessedEvent{
+		EventID:     origin.EventID,
+		OrderID:     origin.OrderID,
+		Action:      action,
+		Items:       items,
+		ProcessedAt: now,
+		ExpiresAt:   now.Add(s.dedupeTTL),
+	}
+}
+
+// stockOutcome - 재고 차감 결과를 메트릭 outcome으로 분류
+func stockOutcome(err error) string {
+	switch {
+	case err == nil:
+		return metrics.StockOutcomeSuccess
+	case errors.Is(err, ErrDuplicateEvent):
+		return metrics.StockOutcomeDuplicate
+	case errors.Is(err, ErrInsufficientStock):
+		return metrics.StockOutcomeInsufficient
+	case errors.Is(err, ErrProductNotFound):
+		return metrics.StockOutcomeNotFound
+	case errors.Is(err, ErrInvalidBatch):
+		return metrics.StockOutcomeInvalid
+	default:
+		return metrics.StockOutcomeError
+	}
+}
+
+// mergeDeductionItems - 같은 상품의 수량을 합치고 (한 트랜잭션에서 같은 항목을 두 번 쓸 수 없음) 요청을 검증
+func mergeDeductionItems(items []domain.StockDeductionItem) ([]domain.StockDeductionItem, error) {
+	if len(items) == 0 {
+		return nil, fmt.Errorf("%w: no items", ErrInvalidBatch)
+	}
+
+	merged := make([]domain.StockDeductionItem, 0, len(items))
+	index := make(map[string]int, len(items))
+	for _, item := range items {
+		productID, err := domain.NormalizeProductID(item.ProductID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidBatch, err)
+		}
+		item.ProductID = productID
+		if item.Quantity < 1 {
+			return nil, fmt.Errorf("%w: invalid quantity %d for product %s", ErrInvalidBatch, item.Quantity, item.ProductID)
+		}
+		if i, ok := index[item.ProductID]; ok {
+			merged[i].Quantity += item.Quantity
+			continue
+		}
+		index[item.ProductID] = len(merged)
+		merged = append(merged, item)
+	}
+
+	if len(merged) > repository.MaxBatchItems {
+		return nil, fmt.Errorf("%w: at most %d products per batch", ErrInvalidBatch, repository.MaxBatchItems)
+	}
+	return merged, nil
+}
@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cloud-wave-best-zizon/product-service/internal/domain"
+	"github.com/cloud-wave-best-zizon/product-service/internal/repository"
+	"github.com/cloud-wave-best-zizon/product-service/pkg/logging"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrReservationNotFound   = errors.New("reservation not found")
+	ErrReservationNotPending = errors.New("reservation is not pending")
+	ErrReservationExpired    = errors.New("reservation expired")
+	ErrInvalidReservationTTL = errors.New("invalid reservation ttl")
+)
+
+// 스위퍼가 한 번에 처리하는 만료 예약 수
+const sweepBatchSize = 100
+
+// ReservationService - 체크아웃용 2단계 재고 처리 (예약 -> 확정/해제)
+type ReservationService struct {
+	reservationStore repository.ReservationStore
+	defaultTTL       time.Duration
+	maxTTL           time.Duration
+	logger           *zap.Logger
+}
+
+// NewReservationService - 요청에 ttl_seconds가 없으면 defaultTTL, maxTTL보다 긴 예약은 거절 (0이면 제한 없음)
+func NewReservationService(reservationStore repository.ReservationStore, defaultTTL, maxTTL time.Duration, logger *zap.Logger) *ReservationService {
+	return &ReservationService{
+		reservationStore: reservationStore,
+		defaultTTL:       defaultTTL,
+		maxTTL:           maxTTL,
+		logger:           logger,
+	}
+}
+
+// log - ctx의 요청 ID(request_id)를 붙인 로거
+func (s *ReservationService) log(ctx context.Context) *zap.Logger {
+	return logging.FromContext(ctx, s.logger)
+}
+
+func (s *ReservationService) Reserve(ctx context.Context, req domain.CreateReservationRequest) (*domain.Reservation, error) {
+	productID, err := domain.NormalizeProductID(req.ProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := s.defaultTTL
+	if req.TTLSeconds > 0 {
+		ttl = time.Duration(req.TTLSeconds) * time.Second
+	}
+	if s.maxTTL > 0 && ttl > s.maxTTL {
+		return nil, fmt.Errorf("%w: ttl_seconds must be at most %d", ErrInvalidReservationTTL, int(s.maxTTL.Seconds()))
+	}
+
+	now := time.Now()
+	reservation := &domain.Reservation{
+		ReservationID: uuid.New().String(),
+		ProductID:     productID,
+		Quantity:      req.Quantity,
+		Status:        domain.ReservationPending,
+		ExpiresAt:     now.Add(ttl),
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	if err := s.reservationStore.CreateReservation(ctx, reservation); err != nil {
+		var itemErr *domain.StockItemError
+		if errors.As(err, &itemErr) {
+			switch {
+			case errors.Is(itemErr.Err, repository.ErrProductNotFound):
+				return nil, ErrProductNotFound
+			case errors.Is(itemErr.Err, repository.ErrInsufficientStock):
+				return nil, &domain.StockItemError{ProductID: itemErr.ProductID, Available: itemErr.Available, Err: ErrInsufficientStock}
+			}
+		}
+		s.log(ctx).Error("Failed to create reservation",
+			zap.String("product_id", productID),
+			zap.Error(err))
+		return nil, err
+	}
+
+	s.log(ctx).Info("Stock reserved successfully",
+		zap.String("reservation_id", reservation.ReservationID),
+		zap.String("product_id", reservation.ProductID),
+		zap.Int("quantity", reservation.Quantity),
+		zap.Time("expires_at", reservation.ExpiresAt))
+
+	return reservation, nil
+}
+
+func (s *ReservationService) GetReservation(ctx context.Context, reservationID string) (*domain.Reservation, error) {
+	reservation, err := s.reservationStore.GetReservation(ctx, reservationID)
+	if err != nil {
+		return nil, reservationError(err)
+	}
+	return reservation, nil
+}
+
+func (s *ReservationService) Confirm(ctx context.Context, reservationID string) (*domain.Reservation, error) {
+	reservation, err := s.reservationStore.ConfirmReservation(ctx, reservationID, time.Now())
+	if err != nil {
+		return nil, s.logReservationError(ctx, reservationError(err), reservationID, "Failed to confirm reservation")
+	}
+
+	s.log(ctx).Info("Reservation confirmed",
+		zap.String("reservation_id", reservationID),
+		zap.String("product_id", reservation.ProductID),
+		zap.Int("quantity", reservation.Quantity))
+
+	return reservation, nil
+}
+
+func (s *ReservationService) Release(ctx context.Context, reservationID string) (*domain.Reservation, error) {
+	reservation, err := s.reservationStore.ReleaseReservation(ctx, reservationID, domain.ReservationReleased)
+	if err != nil {
+		return nil, s.logReservationError(ctx, reservationError(err), reservationID, "Failed to release reservation")
+	}
+
+	s.log(ctx).Info("Reservation released",
+		zap.String("reservation_id", reservationID),
+		zap.String("product_id", reservation.ProductID),
+		zap.Int("quantity", reservation.Quantity))
+
+	return reservation, nil
+}
+
+// SweepExpired - 만료된 pending 예약을 해제하고 처리 건수를 반환
+func (s *ReservationService) SweepExpired(ctx context.Context) (int, error) {
+	expired, err := s.reservationStore.ListExpiredReservations(ctx, time.Now(), sweepBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	released := 0
+	for _, reservation := range expired {
+		_, err := s.reservationStore.ReleaseReservation(ctx, reservation.ReservationID, domain.ReservationExpired)
+		if err != nil {
+			// 그 사이 확정/해제된 예약은 건너뜀
+			if errors.Is(err, repository.ErrReservationNotPending) {
+				continue
+			}
+			s.log(ctx).Error("Failed to release expired reservation",
+				zap.String("reservation_id", reservation.ReservationID),
+				zap.Error(err))
+			continue
+		}
+
+		released++
+		s.log(ctx).Info("Expired reservation released",
+			zap.String("reservation_id", reservation.ReservationID),
+			zap.String("product_id", reservation.ProductID),
+			zap.Int("quantity", reservation.Quantity))
+	}
+
+	return released, nil
+}
+
+// RunSweeper - ctx가 취소될 때까지 interval마다 만료 예약을 해제
+func (s *ReservationService) RunSweeper(ctx context.Context, interval time.Duration) {
+	s.log(ctx).Info("Starting reservation sweeper", zap.Duration("interval", interval))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.log(ctx).Info("Reservation sweeper stopped")
+			return
+		case <-ticker.C:
+			if _, err := s.SweepExpired(ctx); err != nil && ctx.Err() == nil {
+				s.log(ctx).Error("Failed to sweep expired reservations", zap.Error(err))
+			}
+		}
+	}
+}
+
+func (s *ReservationService) logReservationError(ctx context.Context, err error, reservationID, message string) error {
+	switch err {
+	case ErrReservationNotFound, ErrReservationNotPending, ErrReservationExpired:
+		return err
+	}
+
+	s.log(ctx).Error(message,
+		zap.String("reservation_id", reservationID),
+		zap.Error(err))
+	return err
+}
+
+func reservationError(err error) error {
+	switch {
+	case errors.Is(err, repository.ErrReservationNotFound):
+		return ErrReservationNotFound
+	case errors.Is(err, repository.ErrReservationNotPending):
+		return ErrReservationNotPending
+	case errors.Is(err, repository.ErrReservationExpired):
+		return ErrReservationExpired
+	}
+	return err
+}
@@ -1,22 +1,104 @@
 package config
 
 import (
+	"strings"
+	"time"
+
 	"github.com/kelseyhightower/envconfig"
 )
 
 type Config struct {
 	Port             string `envconfig:"PORT" default:"8081"`
+	AdminPort        string `envconfig:"ADMIN_PORT" default:"9090"` // /metrics 등 운영용 엔드포인트 (비우면 비활성화)
 	AWSRegion        string `envconfig:"AWS_REGION" default:"ap-northeast-2"`
 	ProductTableName string `envconfig:"PRODUCT_TABLE_NAME" default:"products-table"`
 	LogLevel         string `envconfig:"LOG_LEVEL" default:"info"`
+	LogFormat        string `envconfig:"LOG_FORMAT" default:"json"` // json | console
 	LocalMode        bool   `envconfig:"LOCAL_MODE" default:"false"`
 	DynamoDBEndpoint string `envconfig:"DYNAMODB_ENDPOINT" default:""`
 	TLSEnabled       bool   `envconfig:"TLS_ENABLED" default:"false"`
-	
+
+	// 로그 샘플링 (1초마다 같은 메시지를 INITIAL개까지 남기고 이후 THEREAFTER개마다 하나, INITIAL=0이면 끔)
+	LogSamplingInitial    int `envconfig:"LOG_SAMPLING_INITIAL" default:"100"`
+	LogSamplingThereafter int `envconfig:"LOG_SAMPLING_THEREAFTER" default:"100"`
+
+	// /readyz 의존성 확인 제한 시간 (컴포넌트별)
+	HealthCheckTimeout time.Duration `envconfig:"HEALTH_CHECK_TIMEOUT" default:"2s"`
+
+	// 재고 예약 설정
+	ReservationTableName     string        `envconfig:"RESERVATION_TABLE_NAME" default:"product-reservations"`
+	ReservationTTL           time.Duration `envconfig:"RESERVATION_TTL" default:"15m"`
+	ReservationMaxTTL        time.Duration `envconfig:"RESERVATION_MAX_TTL" default:"2h"` // 요청의 ttl_seconds 상한
+	ReservationSweepInterval time.Duration `envconfig:"RESERVATION_SWEEP_INTERVAL" default:"30s"`
+
+	// 트랜잭셔널 아웃박스 설정
+	OutboxTableName    string        `envconfig:"OUTBOX_TABLE_NAME" default:"product-outbox"`
+	OutboxPollInterval time.Duration `envconfig:"OUTBOX_POLL_INTERVAL" default:"1s"`
+	OutboxBatchSize    int           `envconfig:"OUTBOX_BATCH_SIZE" default:"100"`
+	OutboxRetryBase    time.Duration `envconfig:"OUTBOX_RETRY_BASE" default:"1s"`
+	OutboxRetryMax     time.Duration `envconfig:"OUTBOX_RETRY_MAX" default:"5m"`
+
+	// 주문 이벤트 중복 처리 방지 설정
+	ProcessedEventTableName string        `envconfig:"PROCESSED_EVENT_TABLE_NAME" default:"product-processed-events"`
+	DedupeTTL               time.Duration `envconfig:"DEDUPE_TTL" default:"168h"`
+
 	// Kafka 설정
-	KafkaBrokers   string `envconfig:"KAFKA_BROKERS" default:"localhost:9092"`
-	KafkaGroupID   string `envconfig:"KAFKA_GROUP_ID" default:"product-service"`
-	KafkaEnabled   bool   `envconfig:"KAFKA_ENABLED" default:"true"`
+	KafkaBrokers    string `envconfig:"KAFKA_BROKERS" default:"localhost:9092"` // 콤마로 구분된 브로커 목록
+	KafkaGroupID    string `envconfig:"KAFKA_GROUP_ID" default:"product-service-consumer"`
+	KafkaEnabled    bool   `envconfig:"KAFKA_ENABLED" default:"true"`
+	KafkaOrderTopic string `envconfig:"KAFKA_ORDER_TOPIC" default:"order-events"`
+	KafkaStockTopic string `envconfig:"KAFKA_STOCK_TOPIC" default:"stock-events"`
+	KafkaReplyTopic string `envconfig:"KAFKA_REPLY_TOPIC" default:"stock-reservation-events"`
+
+	// Kafka 프로듀서 (메시지 키 기준과 파티션 선택 방식)
+	KafkaProducerBalancer string `envconfig:"KAFKA_PRODUCER_BALANCER" default:"hash"`        // hash | murmur2 | least_bytes
+	KafkaOrderKeyStrategy string `envconfig:"KAFKA_ORDER_KEY_STRATEGY" default:"order_id"`   // order_id | product_id | event_id
+	KafkaStockKeyStrategy string `envconfig:"KAFKA_STOCK_KEY_STRATEGY" default:"product_id"` // order_id | product_id | event_id
+	KafkaEventEncoding    string `envconfig:"KAFKA_EVENT_ENCODING" default:"json"`           // json | protobuf (스키마가 없는 이벤트는 항상 json)
+
+	// Kafka 컨슈머 튜닝
+	KafkaStartOffset    string        `envconfig:"KAFKA_START_OFFSET" default:"first"` // first | last (커밋된 오프셋이 없을 때)
+	KafkaMinBytes       int           `envconfig:"KAFKA_MIN_BYTES" default:"10000"`
+	KafkaMaxBytes       int           `envconfig:"KAFKA_MAX_BYTES" default:"10000000"`
+	KafkaCommitInterval time.Duration `envconfig:"KAFKA_COMMIT_INTERVAL" default:"0s"` // 0이면 동기 커밋
+	KafkaWorkers        int           `envconfig:"KAFKA_CONSUMER_WORKERS" default:"1"`
+	KafkaOrdering       string        `envconfig:"KAFKA_CONSUMER_ORDERING" default:"partition"` // partition | key
+
+	// Kafka 브로커 연결 보안 (TLS, SASL)
+	KafkaTLSEnabled            bool   `envconfig:"KAFKA_TLS_ENABLED" default:"false"`
+	KafkaTLSCAFile             string `envconfig:"KAFKA_TLS_CA_FILE" default:""`
+	KafkaTLSCertFile           string `envconfig:"KAFKA_TLS_CERT_FILE" default:""`
+	KafkaTLSKeyFile            string `envconfig:"KAFKA_TLS_KEY_FILE" default:""`
+	KafkaTLSServerName         string `envconfig:"KAFKA_TLS_SERVER_NAME" default:""`
+	KafkaTLSInsecureSkipVerify bool   `envconfig:"KAFKA_TLS_INSECURE_SKIP_VERIFY" default:"false"`
+	KafkaTLSUseSVID            bool   `envconfig:"KAFKA_TLS_USE_SVID" default:"false"` // SPIRE SVID를 클라이언트 인증서로 사용
+	KafkaSASLMechanism         string `envconfig:"KAFKA_SASL_MECHANISM" default:""`    // plain | scram-sha-256 | scram-sha-512
+	KafkaSASLUsername          string `envconfig:"KAFKA_SASL_USERNAME" default:""`
+	KafkaSASLPassword          string `envconfig:"KAFKA_SASL_PASSWORD" default:""`
+
+	// 컨슈머 재시도 / DLQ 설정
+	ConsumerRetryMaxAttempts int           `envconfig:"CONSUMER_RETRY_MAX_ATTEMPTS" default:"5"`
+	ConsumerRetryBase        time.Duration `envconfig:"CONSUMER_RETRY_BASE" default:"200ms"`
+	ConsumerRetryMax         time.Duration `envconfig:"CONSUMER_RETRY_MAX" default:"10s"`
+	KafkaDLQTopic            string        `envconfig:"KAFKA_DLQ_TOPIC" default:"order-events.dlq"`
+	KafkaDLQReplayGroupID    string        `envconfig:"KAFKA_DLQ_REPLAY_GROUP_ID" default:"product-service-dlq-replay"`
+
+	// 분산 추적 (OpenTelemetry)
+	TracingExporter     string  `envconfig:"TRACING_EXPORTER" default:"none"` // none | stdout | otlp
+	TracingOTLPEndpoint string  `envconfig:"TRACING_OTLP_ENDPOINT" default:"localhost:4318"`
+	TracingOTLPInsecure bool    `envconfig:"TRACING_OTLP_INSECURE" default:"false"`
+	TracingSampleRatio  float64 `envconfig:"TRACING_SAMPLE_RATIO" default:"1.0"`
+}
+
+// KafkaBrokerList - KAFKA_BROKERS를 콤마로 나눈 브로커 주소 목록
+func (c *Config) KafkaBrokerList() []string {
+	var brokers []string
+	for _, broker := range strings.Split(c.KafkaBrokers, ",") {
+		if broker = strings.TrimSpace(broker); broker != "" {
+			brokers = append(brokers, broker)
+		}
+	}
+	return brokers
 }
 
 func Load() (*Config, error) {
@@ -25,4 +107,4 @@ func Load() (*Config, error) {
 		return nil, err
 	}
 	return &cfg, nil
-}
\ No newline at end of file
+}
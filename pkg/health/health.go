@@ -0,0 +1,167 @@
+package health
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// 컴포넌트 및 전체 상태 값
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+
+	StatusOK          = "ok"
+	StatusDegraded    = "degraded"    // 선택(optional) 컴포넌트만 실패
+	StatusUnavailable = "unavailable" // 필수 컴포넌트 실패 (503)
+)
+
+// DefaultTimeout - 컴포넌트 하나의 확인 제한 시간 (0 이하로 생성했을 때)
+const DefaultTimeout = 2 * time.Second
+
+// Check - 의존성 하나의 상태 확인
+type Check struct {
+	Name     string
+	Check    func(ctx context.Context) error
+	Optional bool // 실패해도 503을 반환하지 않음 (degraded로만 표시)
+}
+
+// ComponentStatus - 컴포넌트별 확인 결과
+type ComponentStatus struct {
+	Status    string  `json:"status"`
+	Required  bool    `json:"required"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Report - /livez, /readyz 응답 본문
+type Report struct {
+	Status     string                     `json:"status"`
+	Components map[string]ComponentStatus `json:"components"`
+}
+
+// Healthy - 필수 컴포넌트가 모두 정상인지 여부
+func (r Report) Healthy() bool {
+	return r.Status != StatusUnavailable
+}
+
+// Checker - liveness(프로세스 재시작 판단)와 readiness(트래픽 수신 판단) 확인 목록
+type Checker struct {
+	timeout   time.Duration
+	mu        sync.RWMutex
+	liveness  []Check
+	readiness []Check
+}
+
+func NewChecker(timeout time.Duration) *Checker {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Checker{timeout: timeout}
+}
+
+// AddLiveness - 실패하면 재시작이 필요한 확인 (예: 컨슈머 루프 종료)
+func (c *Checker) AddLiveness(check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.liveness = append(c.liveness, check)
+}
+
+// AddReadiness - 실패하면 트래픽을 받지 않아야 하는 확인 (DynamoDB, Kafka, SVID 등)
+func (c *Checker) AddReadiness(check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.readiness = append(c.readiness, check)
+}
+
+// Live - liveness 확인을 동시에 실행
+func (c *Checker) Live(ctx context.Context) Report {
+	c.mu.RLock()
+	checks := append([]Check(nil), c.liveness...)
+	c.mu.RUnlock()
+	return c.run(ctx, checks)
+}
+
+// Ready - liveness와 readiness 확인을 모두 동시에 실행 (죽은 프로세스는 준비되지 않은 것으로 본다)
+func (c *Checker) Ready(ctx context.Context) Report {
+	c.mu.RLock()
+	checks := append(append([]Check(nil), c.liveness...), c.readiness...)
+	c.mu.RUnlock()
+	return c.run(ctx, checks)
+}
+
+func (c *Checker) run(ctx context.Context, checks []Check) Report {
+	results := make([]ComponentStatus, len(checks))
+
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			results[i] = c.runOne(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Components: make(map[string]ComponentStatus, len(checks))}
+	for i, check := range checks {
+		report.Components[check.Name] = results[i]
+		if results[i].Status == StatusUp {
+			continue
+		}
+		if check.Optional {
+			if report.Status == StatusOK {
+				report.Status = StatusDegraded
+			}
+		} else {
+			report.Status = StatusUnavailable
+		}
+	}
+	return report
+}
+
+// runOne - 제한 시간 안에 끝나지 않으면 확인 함수가 ctx를 무시하더라도 실패로 처리
+func (c *Checker) runOne(ctx context.Context, check Check) ComponentStatus {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- check.Check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	status := ComponentStatus{
+		Status:    StatusUp,
+		Required:  !check.Optional,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		status.Status = StatusDown
+		status.Error = err.Error()
+	}
+	return status
+}
+
+// Names - 등록된 모든 컴포넌트 이름 (시작 로그용)
+func (c *Checker) Names() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	names := make([]string, 0, len(c.liveness)+len(c.readiness))
+	for _, check := range c.liveness {
+		names = append(names, check.Name)
+	}
+	for _, check := range c.readiness {
+		names = append(names, check.Name)
+	}
+	sort.Strings(names)
+	return names
+}
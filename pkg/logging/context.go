@@ -0,0 +1,32 @@
+package logging
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+type requestIDKey struct{}
+
+// WithRequestID - 요청 ID를 ctx에 저장 (비어 있으면 ctx를 그대로 반환)
+// HTTP 요청은 middleware.RequestID가, Kafka 메시지는 컨슈머가 저장한다.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	if requestID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext - ctx에 저장된 요청 ID (없으면 빈 문자열)
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// FromContext - ctx의 요청 ID를 request_id 필드로 붙인 로거 (없으면 logger 그대로)
+func FromContext(ctx context.Context, logger *zap.Logger) *zap.Logger {
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		return logger.With(zap.String("request_id", requestID))
+	}
+	return logger
+}
@@ -0,0 +1,58 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// 로그 출력 형식 (LOG_FORMAT)
+const (
+	FormatJSON    = "json"
+	FormatConsole = "console"
+)
+
+// Config - 로거 설정 (SamplingInitial이 0이면 샘플링하지 않음)
+type Config struct {
+	Level              string
+	Format             string
+	SamplingInitial    int // 1초마다 같은 메시지를 그대로 남기는 개수
+	SamplingThereafter int // 그 이후에는 N개마다 하나만 남김
+}
+
+// New - 설정으로 로거를 만들고, 실행 중 레벨을 바꿀 수 있는 AtomicLevel을 함께 반환
+func New(cfg Config) (*zap.Logger, zap.AtomicLevel, error) {
+	level, err := zap.ParseAtomicLevel(strings.ToLower(cfg.Level))
+	if err != nil {
+		return nil, level, fmt.Errorf("invalid log level %q: %w", cfg.Level, err)
+	}
+
+	zcfg := zap.NewProductionConfig()
+	zcfg.Level = level
+	switch strings.ToLower(cfg.Format) {
+	case "", FormatJSON:
+		zcfg.Encoding = FormatJSON
+	case FormatConsole:
+		zcfg.Encoding = FormatConsole
+		zcfg.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+		zcfg.EncoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+	default:
+		return nil, level, fmt.Errorf("invalid log format %q (want json or console)", cfg.Format)
+	}
+
+	zcfg.Sampling = nil
+	if cfg.SamplingInitial > 0 {
+		zcfg.Sampling = &zap.SamplingConfig{
+			Initial:    cfg.SamplingInitial,
+			Thereafter: cfg.SamplingThereafter,
+		}
+	}
+
+	logger, err := zcfg.Build()
+	if err != nil {
+		return nil, level, fmt.Errorf("failed to build logger: %w", err)
+	}
+	return logger, level, nil
+}
@@ -0,0 +1,26 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go/middleware"
+)
+
+// DynamoDBAPIOption - DynamoDB 클라이언트 호출마다 operation별 지연 시간을 기록하는 SDK 미들웨어
+// dynamodb.Options.APIOptions에 추가한다.
+func DynamoDBAPIOption(stack *middleware.Stack) error {
+	return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("PrometheusLatency",
+		func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+			start := time.Now()
+			out, metadata, err := next.HandleInitialize(ctx, in)
+
+			outcome := "success"
+			if err != nil {
+				outcome = "error"
+			}
+			DynamoDBRequestDuration.WithLabelValues(awsmiddleware.GetOperationName(ctx), outcome).Observe(time.Since(start).Seconds())
+			return out, metadata, err
+		}), middleware.After)
+}
@@ -0,0 +1,140 @@
+package metrics
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "product_service"
+
+// 재고 차감 결과 (stock_deductions_total의 outcome)
+const (
+	StockOutcomeSuccess      = "success"
+	StockOutcomeInsufficient = "insufficient"
+	StockOutcomeNotFound     = "not_found"
+	StockOutcomeInvalid      = "invalid"
+	StockOutcomeDuplicate    = "duplicate"
+	StockOutcomeError        = "error"
+)
+
+// Registry - 서비스 메트릭 레지스트리 (Go 런타임/프로세스 메트릭 포함)
+var Registry = prometheus.NewRegistry()
+
+var (
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "HTTP requests by method, route and status.",
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency by method, route and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	DynamoDBRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "dynamodb",
+		Name:      "request_duration_seconds",
+		Help:      "DynamoDB call latency (including SDK retries) by operation and outcome.",
+		Buckets:   []float64{.002, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"operation", "outcome"})
+
+	StockDeductions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "stock",
+		Name:      "deductions_total",
+		Help:      "Stock deduction requests (HTTP and order events) by outcome.",
+	}, []string{"outcome"})
+
+	KafkaConsumerLag = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "kafka_consumer",
+		Name:      "lag",
+		Help:      "Messages behind the partition high watermark as of the last fetched message.",
+	}, []string{"topic", "partition"})
+
+	KafkaProcessingDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "kafka_consumer",
+		Name:      "processing_duration_seconds",
+		Help:      "Time to process one consumed message (including retries and DLQ) by event type.",
+		Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"type"})
+
+	KafkaUnknownEvents = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "kafka_consumer",
+		Name:      "unknown_events_total",
+		Help:      "Consumed messages skipped because no handler is registered for their event type.",
+	}, []string{"type"})
+
+	SVIDExpirySeconds = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "tls",
+		Name:      "svid_expiry_seconds",
+		Help:      "Seconds until the current X.509 SVID expires.",
+	}, []string{"spiffe_id"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests,
+		HTTPRequestDuration,
+		DynamoDBRequestDuration,
+		StockDeductions,
+		KafkaConsumerLag,
+		KafkaProcessingDuration,
+		KafkaUnknownEvents,
+		SVIDExpirySeconds,
+	)
+}
+
+// 알 수 없는 이벤트 타입 라벨의 카디널리티 제한 (이후 새 타입과 너무 긴 타입은 "other"로 집계)
+const (
+	maxUnknownEventTypes     = 20
+	maxUnknownEventTypeLabel = 64
+	unknownEventTypeOther    = "other"
+)
+
+var (
+	unknownEventTypesMu sync.Mutex
+	unknownEventTypes   = make(map[string]struct{})
+)
+
+// IncUnknownEvent - 처리 함수가 없는 이벤트 타입을 집계 (프로듀서 스키마 변경 감지용, 라벨 수는 제한)
+func IncUnknownEvent(eventType string) {
+	KafkaUnknownEvents.WithLabelValues(unknownEventLabel(eventType)).Inc()
+}
+
+func unknownEventLabel(eventType string) string {
+	if eventType == "" || len(eventType) > maxUnknownEventTypeLabel {
+		return unknownEventTypeOther
+	}
+
+	unknownEventTypesMu.Lock()
+	defer unknownEventTypesMu.Unlock()
+	if _, ok := unknownEventTypes[eventType]; ok {
+		return eventType
+	}
+	if len(unknownEventTypes) >= maxUnknownEventTypes {
+		return unknownEventTypeOther
+	}
+	unknownEventTypes[eventType] = struct{}{}
+	return eventType
+}
+
+// Handler - /metrics 응답 (Prometheus text format)
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
@@ -1,13 +1,24 @@
 package middleware
 
 import (
+	"net/http"
+	"strconv"
 	"time"
 
+	"github.com/cloud-wave-best-zizon/product-service/pkg/logging"
+	"github.com/cloud-wave-best-zizon/product-service/pkg/metrics"
+	"github.com/cloud-wave-best-zizon/product-service/pkg/tracing"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
+// RequestID - 요청 ID를 gin 컨텍스트와 c.Request.Context()에 저장 (서비스, 저장소 로그에 사용)
 func RequestID() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		requestID := c.GetHeader("X-Request-ID")
@@ -15,6 +26,7 @@ func RequestID() gin.HandlerFunc {
 			requestID = uuid.New().String()
 		}
 		c.Set("request_id", requestID)
+		c.Request = c.Request.WithContext(logging.WithRequestID(c.Request.Context(), requestID))
 		c.Header("X-Request-ID", requestID)
 		c.Next()
 	}
@@ -38,3 +50,53 @@ func Logger(logger *zap.Logger) gin.HandlerFunc {
 		)
 	}
 }
+
+// Metrics - 라우트(등록된 경로 패턴)와 상태 코드별 요청 수와 지연 시간 기록
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := strconv.Itoa(c.Writer.Status())
+		metrics.HTTPRequests.WithLabelValues(c.Request.Method, route, status).Inc()
+		metrics.HTTPRequestDuration.WithLabelValues(c.Request.Method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
+
+// Tracing - 요청 헤더의 W3C trace context를 이어 받아 라우트별 server 스팬 생성
+// 이후 핸들러는 c.Request.Context()로 스팬을 전달받는다.
+func Tracing() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		ctx, span := tracing.Tracer().Start(ctx, c.Request.Method+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", c.Request.Method),
+				attribute.String("http.route", route),
+				attribute.String("url.path", c.Request.URL.Path),
+			))
+		defer span.End()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+		if requestID := c.GetString("request_id"); requestID != "" {
+			span.SetAttributes(attribute.String("request_id", requestID))
+		}
+	}
+}
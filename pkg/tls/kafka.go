@@ -0,0 +1,81 @@
+package tls
+
+import (
+    "context"
+    "crypto/tls"
+    "crypto/x509"
+    "fmt"
+    "os"
+
+    "github.com/spiffe/go-spiffe/v2/spiffetls/tlsconfig"
+    "go.uber.org/zap"
+)
+
+// KafkaTLSOptions - Kafka 브로커 연결용 TLS 설정
+type KafkaTLSOptions struct {
+    CAFile             string // 브로커 인증서 검증용 CA (비어 있으면 시스템 루트, SVID 사용 시 SPIFFE 번들)
+    CertFile           string // 클라이언트 인증서 (UseSVID가 아닐 때)
+    KeyFile            string
+    ServerName         string
+    InsecureSkipVerify bool
+    UseSVID            bool   // 워크로드의 X.509 SVID를 클라이언트 인증서로 사용 (자동 갱신)
+    SocketPath         string // SPIRE agent 소켓
+}
+
+func LoadKafkaTLSConfig(opts KafkaTLSOptions, logger *zap.Logger) (*tls.Config, error) {
+    var roots *x509.CertPool
+    if opts.CAFile != "" {
+        pem, err := os.ReadFile(opts.CAFile)
+        if err != nil {
+            return nil, fmt.Errorf("failed to read kafka CA file: %w", err)
+        }
+        roots = x509.NewCertPool()
+        if !roots.AppendCertsFromPEM(pem) {
+            return nil, fmt.Errorf("no certificates found in kafka CA file %s", opts.CAFile)
+        }
+    }
+
+    var tlsConfig *tls.Config
+    switch {
+    case opts.UseSVID:
+        source, err := getX509Source(context.Background(), opts.SocketPath)
+        if err != nil {
+            return nil, err
+        }
+        if roots == nil && !opts.InsecureSkipVerify {
+            // 브로커도 SPIFFE 인증서를 사용하면 trust bundle로 검증
+            tlsConfig = tlsconfig.MTLSClientConfig(source, source, tlsconfig.AuthorizeAny())
+        } else {
+            tlsConfig = &tls.Config{
+                RootCAs:              roots,
+                GetClientCertificate: tlsconfig.GetClientCertificate(source),
+            }
+        }
+    case opts.CertFile != "" || opts.KeyFile != "":
+        cert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+        if err != nil {
+            return nil, fmt.Errorf("failed to load kafka client certificate: %w", err)
+        }
+        tlsConfig = &tls.Config{
+            RootCAs:      roots,
+            Certificates: []tls.Certificate{cert},
+        }
+    default:
+        tlsConfig = &tls.Config{RootCAs: roots}
+    }
+
+    tlsConfig.MinVersion = tls.VersionTLS12
+    if opts.ServerName != "" {
+        tlsConfig.ServerName = opts.ServerName
+    }
+    if opts.InsecureSkipVerify {
+        tlsConfig.InsecureSkipVerify = true
+    }
+
+    logger.Info("Kafka TLS configuration loaded",
+        zap.Bool("svid_client_cert", opts.UseSVID),
+        zap.Bool("custom_ca", roots != nil),
+        zap.Bool("insecure_skip_verify", opts.InsecureSkipVerify))
+
+    return tlsConfig, nil
+}
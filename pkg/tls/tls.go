@@ -3,9 +3,12 @@ package tls
 import (
     "context"
     "crypto/tls"
+    "errors"
     "fmt"
+    "sync"
     "time"
     
+    "github.com/cloud-wave-best-zizon/product-service/pkg/metrics"
     "github.com/spiffe/go-spiffe/v2/spiffetls/tlsconfig"
     "github.com/spiffe/go-spiffe/v2/workloadapi"
     "go.uber.org/zap"
@@ -16,28 +19,45 @@ type TLSConfig struct {
     SocketPath   string `envconfig:"SPIRE_SOCKET_PATH" default:"unix:///run/spire/sockets/agent.sock"`
 }
 
-var x509Source *workloadapi.X509Source
+var (
+    x509Source   *workloadapi.X509Source
+    x509SourceMu sync.Mutex
+)
 
-func LoadTLSConfig(cfg *TLSConfig, logger *zap.Logger) (*tls.Config, error) {
-    if !cfg.Enabled {
-        logger.Info("TLS is disabled")
-        return nil, nil
+// getX509Source - 프로세스 전체에서 공유하는 SPIRE X509 소스 (처음 호출 시 생성)
+func getX509Source(ctx context.Context, socketPath string) (*workloadapi.X509Source, error) {
+    x509SourceMu.Lock()
+    defer x509SourceMu.Unlock()
+
+    if x509Source != nil {
+        return x509Source, nil
     }
-    
-    ctx := context.Background()
-    
+
     // SPIRE Workload API를 통해 X509 소스 생성
     source, err := workloadapi.NewX509Source(
         ctx,
         workloadapi.WithClientOptions(
-            workloadapi.WithAddr(cfg.SocketPath),
+            workloadapi.WithAddr(socketPath),
         ),
     )
     if err != nil {
         return nil, fmt.Errorf("unable to create X509Source: %w", err)
     }
-    
+
     x509Source = source
+    return source, nil
+}
+
+func LoadTLSConfig(cfg *TLSConfig, logger *zap.Logger) (*tls.Config, error) {
+    if !cfg.Enabled {
+        logger.Info("TLS is disabled")
+        return nil, nil
+    }
+    
+    source, err := getX509Source(context.Background(), cfg.SocketPath)
+    if err != nil {
+        return nil, err
+    }
     
     // mTLS 서버 설정 생성
     tlsConfig := tlsconfig.MTLSServerConfig(source, source, tlsconfig.AuthorizeAny())
@@ -59,20 +79,52 @@ func WatchCertificates(cfg *TLSConfig, reloadFunc func(*tls.Config) error, logge
     ticker := time.NewTicker(30 * time.Second)
     defer ticker.Stop()
     
+    // SPIRE가 자동으로 인증서를 갱신하므로 별도 처리 불필요
+    // 하지만 로깅과 만료 메트릭을 위해 상태 체크
+    checkSVID(logger)
     for range ticker.C {
-        // SPIRE가 자동으로 인증서를 갱신하므로 별도 처리 불필요
-        // 하지만 로깅을 위해 상태 체크
-        svid, err := x509Source.GetX509SVID()
-        if err != nil {
-            logger.Error("Failed to get X509 SVID", zap.Error(err))
-            continue
-        }
-        
-        logger.Info("Certificate status",
-            zap.String("spiffe_id", svid.ID.String()),
-            zap.Time("expiry", svid.Certificates[0].NotAfter),
-            zap.Duration("ttl", time.Until(svid.Certificates[0].NotAfter)))
+        checkSVID(logger)
+    }
+}
+
+// checkSVID - 현재 SVID의 만료까지 남은 시간을 로그와 메트릭(svid_expiry_seconds)으로 기록
+func checkSVID(logger *zap.Logger) {
+    svid, err := x509Source.GetX509SVID()
+    if err != nil {
+        logger.Error("Failed to get X509 SVID", zap.Error(err))
+        return
+    }
+
+    ttl := time.Until(svid.Certificates[0].NotAfter)
+    metrics.SVIDExpirySeconds.WithLabelValues(svid.ID.String()).Set(ttl.Seconds())
+    logger.Info("Certificate status",
+        zap.String("spiffe_id", svid.ID.String()),
+        zap.Time("expiry", svid.Certificates[0].NotAfter),
+        zap.Duration("ttl", ttl))
+}
+
+// ValidateSVID - 현재 X.509 SVID를 받을 수 있고 유효 기간 안에 있는지 확인 (readiness 용)
+func ValidateSVID() error {
+    x509SourceMu.Lock()
+    source := x509Source
+    x509SourceMu.Unlock()
+    if source == nil {
+        return errors.New("X509Source is not initialized")
+    }
+
+    svid, err := source.GetX509SVID()
+    if err != nil {
+        return fmt.Errorf("failed to get X509 SVID: %w", err)
+    }
+    cert := svid.Certificates[0]
+    now := time.Now()
+    if now.Before(cert.NotBefore) {
+        return fmt.Errorf("SVID %s is not valid until %s", svid.ID, cert.NotBefore.Format(time.RFC3339))
+    }
+    if now.After(cert.NotAfter) {
+        return fmt.Errorf("SVID %s expired at %s", svid.ID, cert.NotAfter.Format(time.RFC3339))
     }
+    return nil
 }
 
 // Cleanup 함수 추가
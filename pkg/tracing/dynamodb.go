@@ -0,0 +1,34 @@
+package tracing
+
+import (
+	"context"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go/middleware"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DynamoDBAPIOption - DynamoDB 호출마다 client 스팬(DynamoDB.<Operation>)을 만드는 SDK 미들웨어
+// dynamodb.Options.APIOptions에 추가한다.
+func DynamoDBAPIOption(stack *middleware.Stack) error {
+	return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("OTelSpan",
+		func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+			operation := awsmiddleware.GetOperationName(ctx)
+			ctx, span := Tracer().Start(ctx, "DynamoDB."+operation,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(
+					attribute.String("db.system", "dynamodb"),
+					attribute.String("rpc.system", "aws-api"),
+					attribute.String("rpc.service", "DynamoDB"),
+					attribute.String("rpc.method", operation),
+				))
+			defer span.End()
+
+			out, metadata, err := next.HandleInitialize(ctx, in)
+			if err != nil {
+				RecordError(span, err)
+			}
+			return out, metadata, err
+		}), middleware.After)
+}
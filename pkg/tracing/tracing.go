@@ -0,0 +1,103 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// 스팬 exporter (TRACING_EXPORTER)
+const (
+	ExporterNone   = "none"   // 스팬을 내보내지 않음 (trace context 전달은 유지)
+	ExporterStdout = "stdout" // 로컬 개발용, 표준 출력에 JSON으로 기록
+	ExporterOTLP   = "otlp"   // OTLP/HTTP 컬렉터로 전송
+)
+
+const instrumentationName = "github.com/cloud-wave-best-zizon/product-service"
+
+type Config struct {
+	ServiceName  string
+	Exporter     string
+	OTLPEndpoint string // host:port (예: otel-collector:4318)
+	OTLPInsecure bool
+	SampleRatio  float64 // 부모 스팬이 없을 때의 샘플링 비율 (0~1)
+}
+
+// Init - 전역 TracerProvider와 W3C Trace Context 전파기를 설정
+// 반환된 함수로 종료 시 남은 스팬을 내보낸다.
+func Init(ctx context.Context, cfg Config, logger *zap.Logger) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch cfg.Exporter {
+	case ExporterNone, "":
+		// 전역 no-op provider: 스팬은 기록하지 않고 수신한 trace context만 전달
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case ExporterOTLP:
+		opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(cfg.OTLPEndpoint)}
+		if cfg.OTLPInsecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		exporter, err = otlptracehttp.New(ctx, opts...)
+	default:
+		return nil, fmt.Errorf("invalid tracing exporter %q (expected none, stdout or otlp)", cfg.Exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s trace exporter: %w", cfg.Exporter, err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		attribute.String("service.name", cfg.ServiceName),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	logger.Info("Tracing enabled",
+		zap.String("exporter", cfg.Exporter),
+		zap.String("endpoint", cfg.OTLPEndpoint),
+		zap.Float64("sample_ratio", cfg.SampleRatio))
+	return provider.Shutdown, nil
+}
+
+// Tracer - 서비스 공용 tracer (Init 이전에 호출해도 전역 provider로 위임됨)
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Inject - ctx의 trace context를 헤더 맵에 기록 (아웃박스 메시지처럼 나중에 발행되는 메시지용)
+func Inject(ctx context.Context, headers map[string]string) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.MapCarrier(headers))
+}
+
+// Extract - 헤더 맵의 trace context를 ctx에 반영
+func Extract(ctx context.Context, headers map[string]string) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(headers))
+}
+
+// RecordError - 스팬에 에러를 기록하고 상태를 Error로 설정
+func RecordError(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}